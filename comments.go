@@ -0,0 +1,127 @@
+package instago
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+)
+
+//The rules Instagram applies to new comments. PostComment checks them before sending
+//anything so that a bad comment doesn't cost a request
+const (
+	MaxCommentLength   = 300
+	MaxCommentHashtags = 4
+	MaxCommentURLs     = 1
+)
+
+//The errors returned by ValidateComment (and so by PostComment) for comments Instagram
+//would refuse
+var (
+	ErrCommentEmpty       = errors.New("instago: comment is empty")
+	ErrCommentTooLong     = errors.New("instago: comment is longer than 300 characters")
+	ErrCommentTooManyTags = errors.New("instago: comment has more than 4 hashtags")
+	ErrCommentTooManyURLs = errors.New("instago: comment has more than 1 URL")
+	ErrCommentAllCaps     = errors.New("instago: comment is all capital letters")
+)
+
+var (
+	commentHashtag = regexp.MustCompile(`#[\p{L}\p{N}_]+`)
+	commentURL     = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+)
+
+//Checks a comment against Instagram's rules: it must not be empty, be longer than
+//MaxCommentLength characters, contain more than MaxCommentHashtags hashtags or
+//MaxCommentURLs URLs, or consist entirely of capital letters
+func ValidateComment(text string) error {
+	if text == "" {
+		return ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(text) > MaxCommentLength {
+		return ErrCommentTooLong
+	}
+	if len(commentHashtag.FindAllString(text, -1)) > MaxCommentHashtags {
+		return ErrCommentTooManyTags
+	}
+	if len(commentURL.FindAllString(text, -1)) > MaxCommentURLs {
+		return ErrCommentTooManyURLs
+	}
+
+	hasUpper := false
+	for _, r := range text {
+		if unicode.IsLower(r) {
+			return nil
+		}
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		}
+	}
+	if hasUpper {
+		return ErrCommentAllCaps
+	}
+	return nil
+}
+
+//Gets the comments left on a media object
+//
+//mediaID: The ID of the media object
+func (api *InstagramAPI) MediaComments(mediaID string) ([]Comment, error) {
+	return api.MediaCommentsContext(context.Background(), mediaID)
+}
+
+//Same as MediaComments, but the request is bound to ctx
+func (api *InstagramAPI) MediaCommentsContext(ctx context.Context, mediaID string) ([]Comment, error) {
+	params := getEmptyMap()
+	result, err := api.DoRequestContext(ctx, "media/"+mediaID+"/comments", params)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]Comment, 0)
+	for _, comment := range result.ObjectArray("data") {
+		comments = append(comments, CommentFromAPI(comment))
+	}
+	return comments, nil
+}
+
+//Comments on a media object as the authenticated user (requires OAuth with the comments
+//scope). The comment is checked with ValidateComment first
+//
+//mediaID: The ID of the media object
+//
+//text: The text of the comment
+func (api *InstagramAPI) PostComment(mediaID, text string) (Comment, error) {
+	return api.PostCommentContext(context.Background(), mediaID, text)
+}
+
+//Same as PostComment, but the request is bound to ctx
+func (api *InstagramAPI) PostCommentContext(ctx context.Context, mediaID, text string) (Comment, error) {
+	if err := ValidateComment(text); err != nil {
+		return Comment{}, err
+	}
+	params := getEmptyMap()
+	params["text"] = text
+	result, err := api.DoContext(ctx, http.MethodPost, "media/"+mediaID+"/comments", params)
+	if err != nil {
+		return Comment{}, err
+	}
+	return CommentFromAPI(result.Object("data")), nil
+}
+
+//Removes a comment, either one left by the authenticated user or one left on their own
+//media (requires OAuth with the comments scope)
+//
+//mediaID: The ID of the media object
+//
+//commentID: The ID of the comment to remove
+func (api *InstagramAPI) DeleteComment(mediaID, commentID string) error {
+	return api.DeleteCommentContext(context.Background(), mediaID, commentID)
+}
+
+//Same as DeleteComment, but the request is bound to ctx
+func (api *InstagramAPI) DeleteCommentContext(ctx context.Context, mediaID, commentID string) error {
+	params := getEmptyMap()
+	_, err := api.DoContext(ctx, http.MethodDelete, "media/"+mediaID+"/comments/"+commentID, params)
+	return err
+}
@@ -19,7 +19,11 @@ func main() {
 	var tag string
 	fmt.Scan(&tag)
 
-	tagInfo := api.TagInfo(tag)
+	tagInfo, err := api.TagInfo(tag)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 	fmt.Println("Tag: ", tagInfo.Tag, "Total: ", tagInfo.MediaCount)
 
 	images, pagination, err := api.TagRecent(tag, "", "", 0)
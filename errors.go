@@ -0,0 +1,108 @@
+package instago
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+//Every error returned by DoRequest (and so by every method built on top of it) wraps one
+//of these values, so you can find out what kind of failure happened with errors.Is
+var (
+	//The request never got a complete response (DNS, connection, TLS, timeouts, ...)
+	ErrNetwork = errors.New("instago: network error")
+
+	//Instagram answered with a non-2xx HTTP status and no error details in the body
+	ErrHTTPStatus = errors.New("instago: unexpected HTTP status")
+
+	//The body of the response could not be decoded as JSON
+	ErrMalformedJSON = errors.New("instago: malformed JSON response")
+
+	//Instagram reported an error in the meta block of the response
+	ErrAPI = errors.New("instago: API error")
+)
+
+//The values Instagram uses for error_type in the meta block of a failed response
+const (
+	ErrorTypeAccessToken            = "OAuthAccessTokenException"
+	ErrorTypeParameter              = "OAuthParameterException"
+	ErrorTypePermissions            = "OAuthPermissionsException"
+	ErrorTypeForbidden              = "OAuthForbiddenException"
+	ErrorTypeRateLimit              = "OAuthRateLimitException"
+	ErrorTypeNotFound               = "APINotFoundError"
+	ErrorTypeNotAllowed             = "APINotAllowedError"
+	ErrorTypeInvalidParameters      = "APIInvalidParametersError"
+	ErrorTypeRequiresAuthentication = "APIRequiresAuthenticationError"
+	ErrorTypeSubscription           = "APISubscriptionError"
+	ErrorTypeGeneric                = "APIError"
+)
+
+//A snapshot of the X-Ratelimit-Limit and X-Ratelimit-Remaining headers sent back by
+//Instagram
+type RateLimit struct {
+	Limit     int
+	Remaining int
+}
+
+//APIError describes a request that Instagram turned down. Code, Type and Message come
+//from the meta block of the response; if Instagram answered with a non-2xx status and
+//no meta block only StatusCode and Message are set. It wraps ErrAPI (or ErrHTTPStatus
+//when there was no meta block) so it can be inspected with both errors.Is and errors.As
+//
+//RetryAfter is how long the response's Retry-After header asked to wait, if it had one
+type APIError struct {
+	StatusCode int
+	Code       int
+	Type       string
+	Message    string
+	RateLimit  RateLimit
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if e.Type == "" && e.Code == 0 {
+		return fmt.Sprintf("instago: HTTP %d %s (RateLimitRemaining: %v)", e.StatusCode, e.Message, e.RateLimit.Remaining)
+	}
+	return fmt.Sprintf("instago: %v [code:%v] %v (RateLimitRemaining: %v)", e.Type, e.Code, e.Message, e.RateLimit.Remaining)
+}
+
+func (e *APIError) Unwrap() error {
+	if e.Type == "" && e.Code == 0 {
+		return ErrHTTPStatus
+	}
+	return ErrAPI
+}
+
+//Reports whether err was caused by going over Instagram's rate limit
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Type == ErrorTypeRateLimit || apiErr.StatusCode == http.StatusTooManyRequests
+}
+
+//Reports whether err was caused by a missing, invalid or expired access token, in which
+//case the user needs to authenticate again
+func IsAuthError(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Type {
+	case ErrorTypeAccessToken, ErrorTypeRequiresAuthentication:
+		return true
+	}
+	return apiErr.StatusCode == http.StatusUnauthorized
+}
+
+//Reports whether err was caused by asking for a user, media object, tag or location that
+//does not exist
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	return apiErr.Type == ErrorTypeNotFound || apiErr.StatusCode == http.StatusNotFound
+}
@@ -0,0 +1,279 @@
+package instago
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+//How many media IDs a RealtimeFetcher remembers to avoid emitting the same media twice
+const fetcherSeenLimit = 10000
+
+//RealtimeFetcher turns realtime updates ("tag X changed") into the media that was actually
+//posted. For each tag, location, geography or user it keeps a watermark (the min_tag_id
+//or the newest media ID it has seen) and only asks Instagram for media newer than that,
+//following as many pages as it takes to get back to the watermark. Updates for the same
+//object that arrive within Coalesce of each other are handled with a single fetch, and
+//media that has already been emitted is never emitted again.
+//
+//Register it with a RealtimeHandler (or call Notify yourself) and call Run in its own
+//goroutine. OnMedia is called from the Run goroutine with each new media object, oldest
+//first; OnError (optional) is told about requests that failed
+type RealtimeFetcher struct {
+	API      *InstagramAPI
+	OnMedia  func(Media)
+	OnError  func(RealtimeUpdate, error)
+	Coalesce time.Duration
+	Count    int
+
+	mu         sync.Mutex
+	pending    map[string]RealtimeUpdate
+	order      []string
+	watermarks map[string]string
+	seen       map[string]bool
+	seenOrder  []string
+	wake       chan struct{}
+}
+
+//Creates a RealtimeFetcher that uses api for its requests, coalesces updates that arrive
+//within a second of each other and passes new media to onMedia
+func NewRealtimeFetcher(api *InstagramAPI, onMedia func(Media)) *RealtimeFetcher {
+	return &RealtimeFetcher{API: api, OnMedia: onMedia, Coalesce: time.Second}
+}
+
+//Makes the fetcher handle every tag, location, geography and user update received by
+//handler
+func (fetcher *RealtimeFetcher) Register(handler *RealtimeHandler) {
+	for _, object := range []SubscriptionObject{SubscriptionTag, SubscriptionLocation, SubscriptionGeography, SubscriptionUser} {
+		handler.Handle(object, fetcher.Notify)
+	}
+}
+
+//Queues an update to be fetched by Run. It never blocks, so it is safe to call straight
+//from a RealtimeHandler callback
+func (fetcher *RealtimeFetcher) Notify(update RealtimeUpdate) {
+	fetcher.mu.Lock()
+	fetcher.init()
+	key := fetcherKey(update.Object, update.ObjectID)
+	if queued, ok := fetcher.pending[key]; !ok {
+		fetcher.order = append(fetcher.order, key)
+	} else if queued.MediaID != update.MediaID {
+		//A single media ID can't stand for several posts, so page back to the watermark
+		//instead
+		update.MediaID = ""
+	}
+	fetcher.pending[key] = update
+	fetcher.mu.Unlock()
+
+	select {
+	case fetcher.wake <- struct{}{}:
+	default:
+	}
+}
+
+//Sets the watermark for an object, for instance to resume where a previous run stopped.
+//For tags it is a min_tag_id (Pagination.MinTagId), for everything else a media ID
+func (fetcher *RealtimeFetcher) SetWatermark(object SubscriptionObject, objectID, watermark string) {
+	fetcher.mu.Lock()
+	defer fetcher.mu.Unlock()
+	fetcher.init()
+	fetcher.watermarks[fetcherKey(object, objectID)] = watermark
+}
+
+//Returns the current watermark for an object, or "" if nothing has been fetched for it
+func (fetcher *RealtimeFetcher) Watermark(object SubscriptionObject, objectID string) string {
+	fetcher.mu.Lock()
+	defer fetcher.mu.Unlock()
+	return fetcher.watermarks[fetcherKey(object, objectID)]
+}
+
+//Fetches the media for queued updates until ctx is done, then returns ctx.Err()
+func (fetcher *RealtimeFetcher) Run(ctx context.Context) error {
+	fetcher.mu.Lock()
+	fetcher.init()
+	fetcher.mu.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-fetcher.wake:
+		}
+		//Give the rest of a burst of updates a chance to arrive
+		if err := sleepContext(ctx, fetcher.Coalesce); err != nil {
+			return err
+		}
+
+		fetcher.mu.Lock()
+		updates := make([]RealtimeUpdate, 0, len(fetcher.order))
+		for _, key := range fetcher.order {
+			updates = append(updates, fetcher.pending[key])
+		}
+		fetcher.pending = make(map[string]RealtimeUpdate)
+		fetcher.order = nil
+		fetcher.mu.Unlock()
+
+		for _, update := range updates {
+			if err := fetcher.fetch(ctx, update); err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				if fetcher.OnError != nil {
+					fetcher.OnError(update, err)
+				}
+			}
+		}
+	}
+}
+
+//Fetches and emits the media that is new since the watermark for the update's object.
+//Instagram returns the newest media first, so pages are followed back until the old
+//watermark is reached; only then does the watermark move. Without a watermark only the
+//first page is fetched, rather than the object's whole history
+func (fetcher *RealtimeFetcher) fetch(ctx context.Context, update RealtimeUpdate) error {
+	key := fetcherKey(update.Object, update.ObjectID)
+	old := fetcher.Watermark(update.Object, update.ObjectID)
+
+	var media []Media
+	watermark := old
+	if update.Object == SubscriptionUser && update.MediaID != "" {
+		single, err := fetcher.API.MediaContext(ctx, update.MediaID)
+		if err != nil {
+			return err
+		}
+		media = []Media{single}
+	} else {
+		query := MediaQuery{After: old, Count: fetcher.Count}
+		for first := true; ; first = false {
+			page, pagination, err := fetcher.fetchPage(ctx, update, query)
+			if err != nil {
+				return err
+			}
+			if first && update.Object == SubscriptionTag && pagination.MinTagId != "" {
+				watermark = pagination.MinTagId
+			}
+			reached := old == ""
+			for _, m := range page {
+				if update.Object == SubscriptionTag {
+					//Tag IDs can't be compared with media IDs, but media that has already
+					//been emitted means the previous fetch has been caught up with
+					reached = reached || fetcher.hasSeen(m.ID)
+				} else if compareMediaIDs(m.ID, old) <= 0 {
+					reached = true
+					continue
+				}
+				media = append(media, m)
+			}
+
+			cursor := pagination.NextMaxId
+			if update.Object == SubscriptionTag {
+				cursor = pagination.NextMaxTagId
+			}
+			if reached || len(page) == 0 || cursor == "" || cursor == query.Before {
+				break
+			}
+			query.Before = cursor
+		}
+	}
+	if update.Object != SubscriptionTag {
+		for _, m := range media {
+			if compareMediaIDs(m.ID, watermark) > 0 {
+				watermark = m.ID
+			}
+		}
+	}
+
+	fetcher.mu.Lock()
+	fetcher.watermarks[key] = watermark
+	fresh := make([]Media, 0, len(media))
+	//Every page lists the newest media first, and later pages hold older media
+	for i := len(media) - 1; i >= 0; i-- {
+		if media[i].ID == "" || fetcher.seen[media[i].ID] {
+			continue
+		}
+		fetcher.remember(media[i].ID)
+		fresh = append(fresh, media[i])
+	}
+	fetcher.mu.Unlock()
+
+	if fetcher.OnMedia != nil {
+		for _, m := range fresh {
+			fetcher.OnMedia(m)
+		}
+	}
+	return nil
+}
+
+//Fetches one page of the media list that belongs to the update's object
+func (fetcher *RealtimeFetcher) fetchPage(ctx context.Context, update RealtimeUpdate, query MediaQuery) ([]Media, Pagination, error) {
+	switch update.Object {
+	case SubscriptionTag:
+		return fetcher.API.TagRecentQueryContext(ctx, update.ObjectID, query)
+	case SubscriptionLocation:
+		return fetcher.API.LocationPostsQueryContext(ctx, update.ObjectID, query)
+	case SubscriptionGeography:
+		return fetcher.API.GeographyRecentMediaContext(ctx, update.ObjectID, query)
+	case SubscriptionUser:
+		return fetcher.API.RecentPostsByUserQueryContext(ctx, update.ObjectID, query)
+	}
+	return nil, Pagination{}, nil
+}
+
+//Reports whether a media object has already been emitted
+func (fetcher *RealtimeFetcher) hasSeen(id string) bool {
+	fetcher.mu.Lock()
+	defer fetcher.mu.Unlock()
+	return fetcher.seen[id]
+}
+
+//Records that a media ID has been emitted, forgetting the oldest one once
+//fetcherSeenLimit IDs are remembered. Must be called with mu held
+func (fetcher *RealtimeFetcher) remember(id string) {
+	fetcher.seen[id] = true
+	fetcher.seenOrder = append(fetcher.seenOrder, id)
+	if len(fetcher.seenOrder) > fetcherSeenLimit {
+		delete(fetcher.seen, fetcher.seenOrder[0])
+		fetcher.seenOrder = fetcher.seenOrder[1:]
+	}
+}
+
+//Allocates the fetcher's maps, so that a RealtimeFetcher literal works too. Must be called
+//with mu held
+func (fetcher *RealtimeFetcher) init() {
+	if fetcher.pending == nil {
+		fetcher.pending = make(map[string]RealtimeUpdate)
+	}
+	if fetcher.watermarks == nil {
+		fetcher.watermarks = make(map[string]string)
+	}
+	if fetcher.seen == nil {
+		fetcher.seen = make(map[string]bool)
+	}
+	if fetcher.wake == nil {
+		fetcher.wake = make(chan struct{}, 1)
+	}
+}
+
+func fetcherKey(object SubscriptionObject, objectID string) string {
+	return string(object) + ":" + objectID
+}
+
+//Orders two media IDs by when the media was posted, by comparing their numeric part
+//(before the _userID suffix). An empty ID is older than everything
+func compareMediaIDs(a, b string) int {
+	a = strings.SplitN(a, "_", 2)[0]
+	b = strings.SplitN(b, "_", 2)[0]
+	switch {
+	case len(a) != len(b):
+		if len(a) < len(b) {
+			return -1
+		}
+		return 1
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,196 @@
+package instago
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+//An API server that answers with canned pages, keyed by path and max_id/max_tag_id, and
+//records the requests it gets
+type fakePages struct {
+	mu       sync.Mutex
+	pages    map[string]string
+	requests []string
+}
+
+func (fake *fakePages) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	fake.mu.Lock()
+	fake.requests = append(fake.requests, fmt.Sprintf("%s min=%s%s max=%s%s", r.URL.Path,
+		query.Get("min_id"), query.Get("min_tag_id"), query.Get("max_id"), query.Get("max_tag_id")))
+	page, ok := fake.pages[r.URL.Path+"|"+query.Get("max_id")+query.Get("max_tag_id")]
+	fake.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, `{"meta": {"code": 200}, %s}`, page)
+}
+
+func (fake *fakePages) Requests() []string {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	return append([]string(nil), fake.requests...)
+}
+
+//Notifies the fetcher of updates, runs it until want media objects have been emitted and
+//returns their IDs
+func runFetcher(t *testing.T, fetcher *RealtimeFetcher, want int, updates ...RealtimeUpdate) []string {
+	emitted := make(chan string, 100)
+	fetcher.OnMedia = func(media Media) { emitted <- media.ID }
+	fetcher.OnError = func(update RealtimeUpdate, err error) { t.Errorf("fetching %+v: %v", update, err) }
+	for _, update := range updates {
+		fetcher.Notify(update)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- fetcher.Run(ctx) }()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	var ids []string
+	timeout := time.After(2 * time.Second)
+	for len(ids) < want {
+		select {
+		case id := <-emitted:
+			ids = append(ids, id)
+		case <-timeout:
+			t.Fatalf("got %v, want %d media objects", ids, want)
+		}
+	}
+	//Give any unexpected extra media a chance to show up
+	time.Sleep(3 * fetcher.Coalesce)
+	close(emitted)
+	for id := range emitted {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func newTestFetcher(t *testing.T, pages map[string]string) (*RealtimeFetcher, *fakePages) {
+	fake := &fakePages{pages: pages}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	fetcher := NewRealtimeFetcher(&InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/"}, nil)
+	fetcher.Coalesce = 10 * time.Millisecond
+	return fetcher, fake
+}
+
+func TestRealtimeFetcherCoalescesBurst(t *testing.T) {
+	fetcher, fake := newTestFetcher(t, map[string]string{
+		"/users/1/media/recent|": `"data": [{"id": "101_1"}, {"id": "100_1"}, {"id": "99_1"}]`,
+	})
+	fetcher.SetWatermark(SubscriptionUser, "1", "99_1")
+
+	ids := runFetcher(t, fetcher, 2,
+		RealtimeUpdate{Object: SubscriptionUser, ObjectID: "1", MediaID: "100_1"},
+		RealtimeUpdate{Object: SubscriptionUser, ObjectID: "1", MediaID: "101_1"},
+	)
+	if want := []string{"100_1", "101_1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("emitted %v, want %v", ids, want)
+	}
+	if want := []string{"/users/1/media/recent min=99_1 max="}; !reflect.DeepEqual(fake.Requests(), want) {
+		t.Errorf("requests %v, want %v", fake.Requests(), want)
+	}
+	if watermark := fetcher.Watermark(SubscriptionUser, "1"); watermark != "101_1" {
+		t.Errorf("watermark %q, want 101_1", watermark)
+	}
+}
+
+func TestRealtimeFetcherSingleMedia(t *testing.T) {
+	fetcher, fake := newTestFetcher(t, map[string]string{
+		"/media/102_1|": `"data": {"id": "102_1"}`,
+	})
+	fetcher.SetWatermark(SubscriptionUser, "1", "101_1")
+
+	ids := runFetcher(t, fetcher, 1,
+		RealtimeUpdate{Object: SubscriptionUser, ObjectID: "1", MediaID: "102_1"},
+		RealtimeUpdate{Object: SubscriptionUser, ObjectID: "1", MediaID: "102_1"},
+	)
+	if want := []string{"102_1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("emitted %v, want %v", ids, want)
+	}
+	if len(fake.Requests()) != 1 {
+		t.Errorf("requests %v, want one", fake.Requests())
+	}
+	if watermark := fetcher.Watermark(SubscriptionUser, "1"); watermark != "102_1" {
+		t.Errorf("watermark %q, want 102_1", watermark)
+	}
+}
+
+func TestRealtimeFetcherTagWatermark(t *testing.T) {
+	fetcher, fake := newTestFetcher(t, map[string]string{
+		"/tags/x/media/recent|": `"pagination": {"min_tag_id": "T9", "next_max_tag_id": "T7"},
+			"data": [{"id": "9_1"}, {"id": "8_1"}]`,
+	})
+
+	//Without a watermark only the first page is fetched
+	ids := runFetcher(t, fetcher, 2, RealtimeUpdate{Object: SubscriptionTag, ObjectID: "x"})
+	if want := []string{"8_1", "9_1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("emitted %v, want %v", ids, want)
+	}
+	if watermark := fetcher.Watermark(SubscriptionTag, "x"); watermark != "T9" {
+		t.Errorf("watermark %q, want the min_tag_id T9", watermark)
+	}
+
+	fake.mu.Lock()
+	fake.pages["/tags/x/media/recent|"] = `"pagination": {"min_tag_id": "T10"}, "data": [{"id": "10_1"}, {"id": "9_1"}]`
+	fake.mu.Unlock()
+	ids = runFetcher(t, fetcher, 1, RealtimeUpdate{Object: SubscriptionTag, ObjectID: "x"})
+	if want := []string{"10_1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("emitted %v, want %v", ids, want)
+	}
+	want := []string{"/tags/x/media/recent min= max=", "/tags/x/media/recent min=T9 max="}
+	if !reflect.DeepEqual(fake.Requests(), want) {
+		t.Errorf("requests %v, want %v", fake.Requests(), want)
+	}
+	if watermark := fetcher.Watermark(SubscriptionTag, "x"); watermark != "T10" {
+		t.Errorf("watermark %q, want T10", watermark)
+	}
+}
+
+func TestRealtimeFetcherCatchesUp(t *testing.T) {
+	fetcher, fake := newTestFetcher(t, map[string]string{
+		"/tags/x/media/recent|":          `"pagination": {"min_tag_id": "T9", "next_max_tag_id": "T7"}, "data": [{"id": "9_1"}, {"id": "8_1"}]`,
+		"/tags/x/media/recent|T7":        `"pagination": {"next_max_tag_id": "T5"}, "data": [{"id": "7_1"}, {"id": "6_1"}]`,
+		"/tags/x/media/recent|T5":        `"pagination": {}, "data": [{"id": "5_1"}]`,
+		"/locations/2/media/recent|":     `"pagination": {"next_max_id": "18_2"}, "data": [{"id": "20_2"}, {"id": "19_2"}]`,
+		"/locations/2/media/recent|18_2": `"pagination": {"next_max_id": "15_2"}, "data": [{"id": "18_2"}, {"id": "16_2"}, {"id": "15_2"}]`,
+	})
+	fetcher.SetWatermark(SubscriptionTag, "x", "T4")
+	fetcher.SetWatermark(SubscriptionLocation, "2", "16_2")
+
+	ids := runFetcher(t, fetcher, 8,
+		RealtimeUpdate{Object: SubscriptionTag, ObjectID: "x"},
+		RealtimeUpdate{Object: SubscriptionLocation, ObjectID: "2"},
+	)
+	want := []string{"5_1", "6_1", "7_1", "8_1", "9_1", "18_2", "19_2", "20_2"}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("emitted %v, want %v", ids, want)
+	}
+	wantRequests := []string{
+		"/tags/x/media/recent min=T4 max=",
+		"/tags/x/media/recent min=T4 max=T7",
+		"/tags/x/media/recent min=T4 max=T5",
+		"/locations/2/media/recent min=16_2 max=",
+		"/locations/2/media/recent min=16_2 max=18_2",
+	}
+	if !reflect.DeepEqual(fake.Requests(), wantRequests) {
+		t.Errorf("requests %v, want %v", fake.Requests(), wantRequests)
+	}
+	if watermark := fetcher.Watermark(SubscriptionTag, "x"); watermark != "T9" {
+		t.Errorf("tag watermark %q, want T9", watermark)
+	}
+	if watermark := fetcher.Watermark(SubscriptionLocation, "2"); watermark != "20_2" {
+		t.Errorf("location watermark %q, want 20_2", watermark)
+	}
+}
@@ -3,6 +3,7 @@
 package instago
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,23 +13,56 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 //The InstagramAPI object stores your credentials. You can obtain a ClientID from
 //http://instagram.com/developer. If you want to interact directly with a user's account
-//you can also obtain an AccessToken through OAuth, however this library currently doesn't
-//support obtaining the ClientID. If the AccessToken is present the ClientID will be
-//ignored (even if the request fails). You should create an InstagramAPI struct with
-//at least one of these values
+//you can also obtain an AccessToken through OAuth (see OAuthConfig). If the AccessToken
+//is present the ClientID will be ignored (even if the request fails). You should create
+//an InstagramAPI struct with at least one of these values
+//
+//HTTPClient is optional and lets you control how requests are sent (timeouts, proxies,
+//custom TLS, App Engine's urlfetch, test transports, ...). If it is nil
+//http.DefaultClient is used
+//
+//BaseURL is optional and lets you send requests to another host, such as a proxy, a
+//compatible mirror or an httptest.Server. If it is empty DefaultBaseURL is used
+//
+//Retry is optional and makes GET requests that fail for a transient reason be retried
+//according to the policy. If it is nil every request is attempted exactly once
+//
+//Limiter is optional and paces requests so they stay within Instagram's rate limit. If
+//it is nil requests are sent as soon as they are made
+//
+//ClientSecret is only needed if your client has "Enforce signed requests" enabled, or to
+//manage realtime subscriptions. When it is set every request carries a sig parameter
+//computed with GenerateSignature
+//
+//A single *InstagramAPI can be shared by many goroutines, as long as its fields are not
+//changed once it is in use. Don't copy an InstagramAPI after its first request; pass a
+//pointer around instead
 type InstagramAPI struct {
-	ClientID           string
-	AccessToken        string
-	RateLimitRemaining int
+	ClientID     string
+	AccessToken  string
+	ClientSecret string
+	HTTPClient   *http.Client
+	BaseURL      string
+	Retry        *RetryPolicy
+	Limiter      *RateLimiter
+
+	//The rate limit reported by the most recent response, see RateLimit
+	mu        sync.Mutex
+	rateLimit RateLimit
 }
 
+//The root of Instagram's API that every endpoint is relative to
+const DefaultBaseURL = "https://api.instagram.com/v1/"
+
 //Represents an media object response from Instagram's servers including key details about the
-//media object. Comments are currently not included.
+//media object. Comments is only the number of comments, use MediaComments to get them.
 type Media struct {
 	Filter                  string
 	Tags                    []string
@@ -47,6 +81,7 @@ type Media struct {
 	CreationTime            time.Time
 	ID                      string
 	Likes                   int
+	UserHasLiked            bool
 	Comments                int
 	Location                Location
 }
@@ -65,6 +100,21 @@ type User struct {
 	TotalFollowers int
 }
 
+//Represents a comment left on a media object, along with the user who wrote it
+type Comment struct {
+	ID           string
+	Text         string
+	CreationTime time.Time
+	From         User
+}
+
+//Represents how the authenticated user and another user are connected
+type Relationship struct {
+	Outgoing            OutgoingStatus
+	Incoming            IncomingStatus
+	TargetUserIsPrivate bool
+}
+
 //Represents a tag and the total number of images with that tag
 type Tag struct {
 	Tag        string
@@ -83,45 +133,248 @@ type Location struct {
 
 // Pagination object
 type Pagination struct {
-	NextMaxTagId string
-	NextMaxId    string
-	NextMinId    string
-	MinTagId     string
-	NextUrl      string
-	NextCursor   string
+	NextMaxTagId  string
+	NextMaxId     string
+	NextMinId     string
+	MinTagId      string
+	NextUrl       string
+	NextCursor    string
+	NextMaxLikeId string
+}
+
+//This will does all GET requests. It returns the JSON object in case of success or an
+//error wrapping ErrNetwork, ErrHTTPStatus, ErrMalformedJSON or ErrAPI in case of failure
+//
+//endpoint: The api request that you want to do on Instagram
+//
+//params: The parameters you may want to add
+func (api *InstagramAPI) DoRequest(endpoint string, params map[string]string) (JSON, error) {
+	return api.DoRequestContext(context.Background(), endpoint, params)
 }
 
-//This will does all GET requests (all Instagram API requests that do not require
-//authentication are GET requests anyway). It returns the JSON object in case of success
-//or an empty object in case of failure
+//Same as DoRequest, but the request is bound to ctx. If ctx is cancelled or its deadline
+//passes before the response has been read, the returned error wraps both ErrNetwork and
+//ctx.Err()
+func (api *InstagramAPI) DoRequestContext(ctx context.Context, endpoint string, params map[string]string) (JSON, error) {
+	return api.DoContext(ctx, http.MethodGet, endpoint, params)
+}
+
+//Sends a request with any HTTP method, which is needed for the endpoints that change
+//something on Instagram (likes, comments, relationships, ...). GET and DELETE requests
+//carry their parameters in the query string, everything else sends them as a
+//form-encoded body. The access token (or client ID) and signature are added either way.
+//If Instagram turns down a DELETE request with 405 Method Not Allowed it is sent again as
+//a POST with _method=DELETE. Only GET requests are retried according to Retry
+//
+//method: The HTTP method, such as http.MethodPost
 //
 //endpoint: The api request that you want to do on Instagram
 //
 //params: The parameters you may want to add
-func (api *InstagramAPI) DoRequest(endpoint string, params map[string]string) JSON {
-	var contents []byte
+func (api *InstagramAPI) Do(method, endpoint string, params map[string]string) (JSON, error) {
+	return api.DoContext(context.Background(), method, endpoint, params)
+}
+
+//Same as Do, but the request is bound to ctx
+func (api *InstagramAPI) DoContext(ctx context.Context, method, endpoint string, params map[string]string) (JSON, error) {
+	return api.do(ctx, method, endpoint, params, userCredentials)
+}
+
+//Which credentials are sent with a request
+type credentials int
+
+const (
+	//The AccessToken if there is one, otherwise the ClientID
+	userCredentials credentials = iota
+	//Only the ClientID, for endpoints that don't accept access tokens
+	clientCredentials
+	//The ClientID and ClientSecret, for managing the application itself
+	appCredentials
+)
 
-	fullURL := api.GetURLForRequest(endpoint, params)
-	resp, err := api.getResponse(fullURL)
+//Sends a request with the given credentials, pacing it with the Limiter and retrying GET
+//requests according to Retry
+func (api *InstagramAPI) do(ctx context.Context, method, endpoint string, params map[string]string, creds credentials) (JSON, error) {
+	for attempt := 1; ; attempt++ {
+		if api.Limiter != nil {
+			if err := api.Limiter.Wait(ctx); err != nil {
+				return nil, networkError(ctx, err)
+			}
+		}
+		result, err := api.doOnce(ctx, method, endpoint, params, creds)
+		if method == http.MethodDelete && isMethodNotAllowed(err) {
+			overridden := make(map[string]string, len(params)+1)
+			for key, value := range params {
+				overridden[key] = value
+			}
+			overridden["_method"] = http.MethodDelete
+			//The fallback is a request of its own and uses up budget like any other
+			if api.Limiter != nil {
+				if err := api.Limiter.Wait(ctx); err != nil {
+					return nil, networkError(ctx, err)
+				}
+			}
+			result, err = api.doOnce(ctx, http.MethodPost, endpoint, overridden, creds)
+		}
+		if err == nil {
+			return result, nil
+		}
+		if api.Limiter != nil && IsRateLimited(err) {
+			var apiErr *APIError
+			errors.As(err, &apiErr)
+			api.Limiter.Exhaust(apiErr.RetryAfter)
+		}
+		if method != http.MethodGet {
+			return nil, err
+		}
+		delay, retry := api.Retry.shouldRetry(ctx, attempt, err)
+		if !retry {
+			return nil, err
+		}
+		api.Retry.notify(RetryEvent{Endpoint: endpoint, Attempt: attempt, Err: err, Delay: delay})
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, networkError(ctx, err)
+		}
+	}
+}
+
+//Makes a single attempt at a request
+func (api *InstagramAPI) doOnce(ctx context.Context, method, endpoint string, params map[string]string, creds credentials) (JSON, error) {
+	req, err := api.newRequest(ctx, method, endpoint, params, creds)
 	if err != nil {
-		contents = []byte("{}")
+		api.Limiter.done()
+		return nil, err
+	}
+	resp, err := api.httpClient().Do(req)
+	//The request has been answered, so the Limiter no longer has to count it as in flight
+	api.Limiter.done()
+	if err != nil {
+		return nil, networkError(ctx, err)
 	}
 	defer resp.Body.Close()
-	contents, err = ioutil.ReadAll(resp.Body)
+	contents, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		contents = []byte("{}")
+		return nil, networkError(ctx, err)
+	}
+	if resp.Header.Get("X-Ratelimit-Remaining") != "" {
+		rateLimit := rateLimitFromHeader(resp.Header)
+		api.mu.Lock()
+		api.rateLimit = rateLimit
+		api.mu.Unlock()
+		if api.Limiter != nil {
+			api.Limiter.Update(rateLimit)
+		}
+	}
+
+	return parseResponse(resp, contents)
+}
+
+//Builds the HTTP request for an endpoint, putting the parameters in the query string or
+//in a form-encoded body depending on the method
+func (api *InstagramAPI) newRequest(ctx context.Context, method, endpoint string, params map[string]string, creds credentials) (*http.Request, error) {
+	if method == http.MethodGet || method == http.MethodDelete {
+		return http.NewRequestWithContext(ctx, method, api.urlForRequest(endpoint, params, creds), nil)
+	}
+	body := api.requestParams(endpoint, params, creds).Encode()
+	req, err := http.NewRequestWithContext(ctx, method, api.baseURL()+endpoint, strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return req, nil
+}
+
+//Reports whether err is Instagram refusing the HTTP method of a request
+func isMethodNotAllowed(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusMethodNotAllowed
+}
+
+//Returns the rate limit budget as of the last response received by any goroutine using
+//this client. If a Limiter is set its view of the budget is returned instead, which also
+//accounts for requests still in flight
+func (api *InstagramAPI) RateLimit() RateLimit {
+	if api.Limiter != nil {
+		return api.Limiter.Budget()
+	}
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	return api.rateLimit
+}
+
+//Reads the rate limit headers that Instagram adds to every response
+func rateLimitFromHeader(header http.Header) RateLimit {
+	limit := RateLimit{}
+	limit.Limit, _ = strconv.Atoi(header.Get("X-Ratelimit-Limit"))
+	limit.Remaining, _ = strconv.Atoi(header.Get("X-Ratelimit-Remaining"))
+	return limit
+}
+
+//Turns the body of a response into JSON, checking both the HTTP status and the meta block
+//that Instagram adds to every response
+func parseResponse(resp *http.Response, contents []byte) (JSON, error) {
+	success := resp.StatusCode >= 200 && resp.StatusCode < 300
+	statusError := &APIError{
+		StatusCode: resp.StatusCode,
+		Message:    http.StatusText(resp.StatusCode),
+		RateLimit:  rateLimitFromHeader(resp.Header),
+		RetryAfter: retryAfterFromHeader(resp.Header),
 	}
-	api.RateLimitRemaining = 0
-	api.RateLimitRemaining, _ = strconv.Atoi(resp.Header.Get("X-Ratelimit-Remaining"))
 	var jsonResponse JSON
-	json.Unmarshal(contents, &jsonResponse)
+	if err := json.Unmarshal(contents, &jsonResponse); err != nil {
+		if !success {
+			return nil, statusError
+		}
+		return nil, fmt.Errorf("%w: %w", ErrMalformedJSON, err)
+	}
+	if apiErr := apiErrorFromMeta(jsonResponse.Object("meta")); apiErr != nil {
+		apiErr.StatusCode = resp.StatusCode
+		apiErr.RateLimit = statusError.RateLimit
+		apiErr.RetryAfter = statusError.RetryAfter
+		return nil, apiErr
+	}
+	if !success {
+		return nil, statusError
+	}
+	return jsonResponse, nil
+}
+
+//getResponse sends a GET request for the given URL through the configured HTTPClient
+func (api *InstagramAPI) getResponse(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return api.httpClient().Do(req)
+}
+
+//Wraps a transport error in ErrNetwork, preferring the context's error when the request
+//failed because ctx was cancelled or timed out
+func networkError(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("%w: %w", ErrNetwork, ctxErr)
+	}
+	return fmt.Errorf("%w: %w", ErrNetwork, err)
+}
 
-	return jsonResponse
+//Returns the BaseURL to build requests on, always ending in a slash
+func (api *InstagramAPI) baseURL() string {
+	base := api.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	if !strings.HasSuffix(base, "/") {
+		base += "/"
+	}
+	return base
 }
 
-// getResponse will get http response using appropriate method (GAE or HTTP)
-func (api InstagramAPI) getResponse(url string) (*http.Response, error) {
-	return http.Get(url)
+//Returns the HTTPClient to send requests with, falling back on http.DefaultClient
+func (api *InstagramAPI) httpClient() *http.Client {
+	if api.HTTPClient != nil {
+		return api.HTTPClient
+	}
+	return http.DefaultClient
 }
 
 //This function will build the request URL so that you can add extra parameters to
@@ -131,25 +384,89 @@ func (api InstagramAPI) getResponse(url string) (*http.Response, error) {
 //
 //params: A map of the extra parameters (aside from client_id) that you want to add to
 //the query
-func (api InstagramAPI) GetURLForRequest(endpoint string, params map[string]string) string {
-	u, err := url.Parse("https://api.instagram.com/v1/" + endpoint)
+func (api *InstagramAPI) GetURLForRequest(endpoint string, params map[string]string) string {
+	return api.urlForRequest(endpoint, params, userCredentials)
+}
+
+//Builds the request URL for an endpoint with the given credentials
+func (api *InstagramAPI) urlForRequest(endpoint string, params map[string]string, creds credentials) string {
+	u, err := url.Parse(api.baseURL() + endpoint)
 	if err != nil {
 		return ""
 	}
 	q := u.Query()
+	for key, value := range api.requestParams(endpoint, params, creds) {
+		q[key] = value
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+//Adds the credentials (and the signature when a ClientSecret is set) to the parameters of
+//a request for the given endpoint
+func (api *InstagramAPI) requestParams(endpoint string, params map[string]string, creds credentials) url.Values {
+	signed := make(map[string]string, len(params)+2)
+	switch {
+	case creds == appCredentials:
+		signed["client_id"] = api.ClientID
+		signed["client_secret"] = api.ClientSecret
 	//If you have an AccessToken (from OAuth), use it
-	if api.AccessToken != "" {
-		q.Set("access_token", api.AccessToken)
-	} else {
-		q.Set("client_id", api.ClientID)
+	case creds == userCredentials && api.AccessToken != "":
+		signed["access_token"] = api.AccessToken
+	default:
+		signed["client_id"] = api.ClientID
 	}
 	for key, value := range params {
-		q.Set(key, value)
+		signed[key] = value
 	}
-	u.RawQuery = q.Encode()
+	if api.ClientSecret != "" && creds != appCredentials {
+		signed["sig"] = GenerateSignature(endpoint, signed, api.ClientSecret)
+	}
+
+	values := url.Values{}
+	for key, value := range signed {
+		values.Set(key, value)
+	}
+	return values
+}
+
+//Pagination.NextUrl always points at Instagram's servers. This returns the same page
+//relative to the configured BaseURL instead, or "" if there is no next page
+func (api *InstagramAPI) NextPageURL(p Pagination) string {
+	if p.NextUrl == "" {
+		return ""
+	}
+	endpoint, query, err := api.endpointFromURL(p.NextUrl)
+	if err != nil {
+		return ""
+	}
+	u, err := url.Parse(api.baseURL() + endpoint)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery = query.Encode()
 	return u.String()
 }
 
+//Splits a full API URL (either on Instagram's servers or on the configured BaseURL) into
+//the endpoint it refers to and its query parameters
+func (api *InstagramAPI) endpointFromURL(rawURL string) (string, url.Values, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, base := range []string{DefaultBaseURL, api.baseURL()} {
+		b, err := url.Parse(base)
+		if err != nil {
+			continue
+		}
+		if u.Host == b.Host && strings.HasPrefix(u.Path, b.Path) {
+			return strings.TrimPrefix(u.Path, b.Path), u.Query(), nil
+		}
+	}
+	return "", nil, fmt.Errorf("instago: %q is not an API URL", rawURL)
+}
+
 //This will take API a JSON object that includes the details for a media object and puts it into
 //the Go data structure for Media.
 //
@@ -164,6 +481,7 @@ func MediaFromAPI(data JSON) Media {
 	media.Link = data.String("link")
 	media.ID = data.String("id")
 	media.Likes = data.Object("likes").Int("count")
+	media.UserHasLiked = data.Bool("user_has_liked")
 	media.Comments = data.Object("comments").Int("count")
 
 	//media caption
@@ -201,6 +519,26 @@ func MediaFromAPI(data JSON) Media {
 	return media
 }
 
+//Takes a comment API JSON response and returns a Comment
+func CommentFromAPI(data JSON) Comment {
+	comment := Comment{}
+	comment.ID = data.String("id")
+	comment.Text = data.String("text")
+	t, _ := strconv.ParseInt(data.String("created_time"), 0, 0)
+	comment.CreationTime = time.Unix(t, 0)
+	comment.From = UserFromAPI(data.Object("from"))
+	return comment
+}
+
+//Takes a relationship API JSON response and returns a Relationship
+func RelationshipFromAPI(data JSON) Relationship {
+	relationship := Relationship{}
+	relationship.Outgoing = OutgoingStatus(data.String("outgoing_status"))
+	relationship.Incoming = IncomingStatus(data.String("incoming_status"))
+	relationship.TargetUserIsPrivate = data.Bool("target_user_is_private")
+	return relationship
+}
+
 //Takes a generic location API JSON response and returns a Location
 func LocationFromAPI(location JSON) Location {
 	loc := Location{}
@@ -241,20 +579,33 @@ func PaginationFromAPI(pagination JSON) Pagination {
 	p.MinTagId = pagination.String("min_tag_id")
 	p.NextUrl = pagination.String("next_url")
 	p.NextCursor = pagination.String("next_cursor")
+	p.NextMaxLikeId = pagination.String("next_max_like_id")
 	return p
 }
 
-func (api InstagramAPI) ErrorFromAPI(result JSON) error {
-	meta := result.Object("meta")
-	code := meta["code"]
-	if code != 200 {
-		error_type := meta.String("error_type")
-		if error_type != "" {
-			error_message := meta.String("error_message")
-			return errors.New(fmt.Sprintf("%v [code:%v] %v (RateLimitRemaining: %v)", error_type, code, error_message, api.RateLimitRemaining))
-		}
+//Checks the meta block of an API response and returns an *APIError if Instagram reported
+//a problem with the request
+func (api *InstagramAPI) ErrorFromAPI(result JSON) error {
+	apiErr := apiErrorFromMeta(result.Object("meta"))
+	if apiErr == nil {
+		return nil
+	}
+	apiErr.RateLimit = api.RateLimit()
+	return apiErr
+}
+
+//Builds an APIError out of a meta block, or returns nil if the meta block reports success
+func apiErrorFromMeta(meta JSON) *APIError {
+	code := meta.Int("code")
+	errorType := meta.String("error_type")
+	if (code == 0 || code == 200) && errorType == "" {
+		return nil
+	}
+	return &APIError{
+		Code:    code,
+		Type:    errorType,
+		Message: meta.String("error_message"),
 	}
-	return nil
 }
 
 //Many queries to Instagram's API simply return a list of media objects (tag, user, location, etc)
@@ -270,18 +621,41 @@ func (api InstagramAPI) ErrorFromAPI(result JSON) error {
 //after: (optional) Search for media objects (posts) after this media ID
 //
 //max: (optional) The great number of media objects to return (there is an imposed limit on this)
-func (api InstagramAPI) GenericMediaListRequest(endPoint, before, after string, max int) ([]Media, Pagination, error) {
-	params := getEmptyMap()
-	if max > 0 {
-		params["count"] = fmt.Sprintf("%d", max)
-	}
-	if before != "" {
-		params["max_id"] = before
-	}
-	if after != "" {
-		params["min_id"] = after
+func (api *InstagramAPI) GenericMediaListRequest(endPoint, before, after string, max int) ([]Media, Pagination, error) {
+	return api.GenericMediaListRequestContext(context.Background(), endPoint, before, after, max)
+}
+
+//Same as GenericMediaListRequest, but the request is bound to ctx
+func (api *InstagramAPI) GenericMediaListRequestContext(ctx context.Context, endPoint, before, after string, max int) ([]Media, Pagination, error) {
+	return api.GenericMediaListQueryContext(ctx, endPoint, MediaQuery{Before: before, After: after, Count: max})
+}
+
+//Same as GenericMediaListRequest, but takes a MediaQuery so that the media can also be
+//narrowed down by time
+//
+//endPoint: The API endpoint, such as /locations/location-id/media/recent
+//
+//query: Which media to return
+func (api *InstagramAPI) GenericMediaListQuery(endPoint string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.GenericMediaListQueryContext(context.Background(), endPoint, query)
+}
+
+//Same as GenericMediaListQuery, but the request is bound to ctx
+func (api *InstagramAPI) GenericMediaListQueryContext(ctx context.Context, endPoint string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.mediaList(ctx, endPoint, query.params(false))
+}
+
+//Requests a list of media objects with the given parameters and parses the response
+func (api *InstagramAPI) mediaList(ctx context.Context, endPoint string, params map[string]string) ([]Media, Pagination, error) {
+	return api.mediaListWith(ctx, endPoint, params, userCredentials)
+}
+
+//Same as mediaList, but sends the given credentials
+func (api *InstagramAPI) mediaListWith(ctx context.Context, endPoint string, params map[string]string, creds credentials) ([]Media, Pagination, error) {
+	results, err := api.do(ctx, http.MethodGet, endPoint, params, creds)
+	if err != nil {
+		return nil, Pagination{}, err
 	}
-	results := api.DoRequest(endPoint, params)
 	data := results.ObjectArray("data")
 	media_objects := make([]Media, 0)
 	for _, media := range data {
@@ -289,25 +663,43 @@ func (api InstagramAPI) GenericMediaListRequest(endPoint, before, after string,
 	}
 
 	pagination := PaginationFromAPI(results.Object("pagination"))
-	err := api.ErrorFromAPI(results)
-	return media_objects, pagination, err
+	return media_objects, pagination, nil
 }
 
-//Download a file from the given URL and save it to the given file
+//Download a file from the given URL and save it to the given file using
+//http.DefaultClient. See InstagramAPI.Download to use a custom HTTPClient
 //Note that the Instagram API encourages you to take into account the IP of Instagram
 //users, so you shouldn't download user's posts with this
-func Download(url, saveFile string) {
+func Download(url, saveFile string) error {
+	return (&InstagramAPI{}).Download(url, saveFile)
+}
+
+//Download a file from the given URL through the configured HTTPClient and save it to the
+//given file
+func (api *InstagramAPI) Download(url, saveFile string) error {
+	return api.DownloadContext(context.Background(), url, saveFile)
+}
+
+//Same as Download, but the transfer is bound to ctx
+func (api *InstagramAPI) DownloadContext(ctx context.Context, url, saveFile string) error {
+	resp, err := api.getResponse(ctx, url)
+	if err != nil {
+		return networkError(ctx, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+
 	out, err := os.Create(saveFile)
 	if err != nil {
-		return
+		return err
 	}
 	defer out.Close()
-
-	resp, err := http.Get(url)
-	if err == nil {
-		defer resp.Body.Close()
-		io.Copy(out, resp.Body)
+	if _, err := io.Copy(out, resp.Body); err != nil {
+		return networkError(ctx, err)
 	}
+	return out.Close()
 }
 
 //Most of the API functions have to get make a map[string] string for parameters so this
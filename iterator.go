@@ -0,0 +1,269 @@
+package instago
+
+import (
+	"context"
+	"errors"
+)
+
+//Returned by Iterator.Err when the iterator stopped to leave RateLimitReserve calls of the
+//rate limit unused
+var ErrRateLimitReserve = errors.New("instago: iteration stopped to keep the rate limit reserve")
+
+//Page is one page of results fetched by an Iterator. Only the slice matching the kind of
+//list being iterated is set. Number counts pages from 1
+type Page struct {
+	Number     int
+	Media      []Media
+	Users      []User
+	Pagination Pagination
+}
+
+//Returns how many items the page holds
+func (page Page) len() int {
+	return len(page.Media) + len(page.Users)
+}
+
+//Iterator walks through every page of a list endpoint, following the pagination field
+//that endpoint uses (max_tag_id for tags, max_like_id for liked media, cursor for
+//followers, and so on). Get one from a method such as TagRecentIterator and use it like
+//this:
+//
+//	it := api.TagRecentIterator("nofilter", instago.MediaQuery{})
+//	it.MaxItems = 500
+//	for it.Next(ctx) {
+//		media := it.Media()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+//
+//MaxItems and MaxPages (0 means no limit) cap how far the iterator goes. If
+//RateLimitReserve is set the iterator stops with ErrRateLimitReserve rather than request
+//a page once no more than that many calls are left in the rate limit, so that the rest of
+//the application isn't starved. An Iterator must not be used from several goroutines at once
+type Iterator struct {
+	MaxItems         int
+	MaxPages         int
+	RateLimitReserve int
+
+	api    *InstagramAPI
+	fetch  func(ctx context.Context, cursor string) (Page, error)
+	next   func(Pagination) string
+	page   Page
+	index  int
+	items  int
+	cursor string
+	done   bool
+	err    error
+}
+
+func (api *InstagramAPI) mediaIterator(next func(Pagination) string, fetch func(ctx context.Context, cursor string) ([]Media, Pagination, error)) *Iterator {
+	return &Iterator{api: api, next: next, fetch: func(ctx context.Context, cursor string) (Page, error) {
+		media, pagination, err := fetch(ctx, cursor)
+		return Page{Media: media, Pagination: pagination}, err
+	}}
+}
+
+func (api *InstagramAPI) userIterator(next func(Pagination) string, fetch func(ctx context.Context, cursor string) ([]User, Pagination, error)) *Iterator {
+	return &Iterator{api: api, next: next, fetch: func(ctx context.Context, cursor string) (Page, error) {
+		users, pagination, err := fetch(ctx, cursor)
+		return Page{Users: users, Pagination: pagination}, err
+	}}
+}
+
+//Moves to the next item, fetching the next page when the current one is used up. It
+//returns false once the list is exhausted, a cap is reached or a request failed; Err
+//tells these apart
+func (it *Iterator) Next(ctx context.Context) bool {
+	if it.done {
+		return false
+	}
+	if it.MaxItems > 0 && it.items >= it.MaxItems {
+		return it.stop(nil)
+	}
+	it.index++
+	for it.page.Number == 0 || it.index >= it.page.len() {
+		if it.page.Number > 0 {
+			cursor := it.next(it.page.Pagination)
+			//A repeated cursor would fetch the same page forever
+			if cursor == "" || cursor == it.cursor {
+				return it.stop(nil)
+			}
+			it.cursor = cursor
+		}
+		if it.MaxPages > 0 && it.page.Number >= it.MaxPages {
+			return it.stop(nil)
+		}
+		if it.RateLimitReserve > 0 {
+			rateLimit := it.api.RateLimit()
+			if rateLimit.Limit > 0 && rateLimit.Remaining <= it.RateLimitReserve {
+				return it.stop(ErrRateLimitReserve)
+			}
+		}
+
+		page, err := it.fetch(ctx, it.cursor)
+		if err != nil {
+			return it.stop(err)
+		}
+		page.Number = it.page.Number + 1
+		it.page = page
+		it.index = 0
+	}
+	it.items++
+	return true
+}
+
+func (it *Iterator) stop(err error) bool {
+	it.done = true
+	it.err = err
+	return false
+}
+
+//Returns the media object Next moved to, for iterators over media
+func (it *Iterator) Media() Media {
+	if it.index < 0 || it.index >= len(it.page.Media) {
+		return Media{}
+	}
+	return it.page.Media[it.index]
+}
+
+//Returns the user Next moved to, for iterators over users
+func (it *Iterator) User() User {
+	if it.index < 0 || it.index >= len(it.page.Users) {
+		return User{}
+	}
+	return it.page.Users[it.index]
+}
+
+//Returns the page the current item comes from, including its Pagination so that the
+//iteration can be resumed later
+func (it *Iterator) Page() Page {
+	return it.page
+}
+
+//Returns the error that stopped the iteration, or nil if it ran to the end of the list or
+//hit MaxItems or MaxPages
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+//The pagination field each kind of endpoint hands out for its next page
+func nextMaxID(p Pagination) string     { return p.NextMaxId }
+func nextMaxTagID(p Pagination) string  { return p.NextMaxTagId }
+func nextMaxLikeID(p Pagination) string { return p.NextMaxLikeId }
+func nextCursor(p Pagination) string    { return p.NextCursor }
+func noNextPage(p Pagination) string    { return "" }
+
+//Iterates over the media recently tagged with tag, newest first
+//
+//tag: The tag name, without the #
+//
+//query: Where to start (Before) or stop (After) and how many media objects to ask for
+//per page (Count)
+func (api *InstagramAPI) TagRecentIterator(tag string, query MediaQuery) *Iterator {
+	return api.mediaIterator(nextMaxTagID, func(ctx context.Context, cursor string) ([]Media, Pagination, error) {
+		if cursor != "" {
+			query.Before = cursor
+		}
+		return api.TagRecentQueryContext(ctx, tag, query)
+	})
+}
+
+//Iterates over the media recently posted at a location, newest first
+//
+//locationId: The ID of the location
+//
+//query: Which media to return, as for LocationPostsQuery
+func (api *InstagramAPI) LocationPostsIterator(locationId string, query MediaQuery) *Iterator {
+	return api.mediaIterator(nextMaxID, func(ctx context.Context, cursor string) ([]Media, Pagination, error) {
+		if cursor != "" {
+			query.Before = cursor
+		}
+		return api.LocationPostsQueryContext(ctx, locationId, query)
+	})
+}
+
+//Iterates over the media posted by a user, newest first. Requires OAuth
+//
+//userId: The ID of the user
+//
+//query: Which media to return, as for RecentPostsByUserQuery
+func (api *InstagramAPI) RecentPostsByUserIterator(userId string, query MediaQuery) *Iterator {
+	return api.mediaIterator(nextMaxID, func(ctx context.Context, cursor string) ([]Media, Pagination, error) {
+		if cursor != "" {
+			query.Before = cursor
+		}
+		return api.RecentPostsByUserQueryContext(ctx, userId, query)
+	})
+}
+
+//Iterates over the media posted by the user the AccessToken belongs to, newest first
+//
+//query: Which media to return, as for SelfRecentMediaQuery
+func (api *InstagramAPI) SelfRecentMediaIterator(query MediaQuery) *Iterator {
+	return api.RecentPostsByUserIterator("self", query)
+}
+
+//Iterates over the current user's feed (requires OAuth)
+//
+//query: Which media to return, as for FeedQuery
+func (api *InstagramAPI) FeedIterator(query MediaQuery) *Iterator {
+	return api.mediaIterator(nextMaxID, func(ctx context.Context, cursor string) ([]Media, Pagination, error) {
+		if cursor != "" {
+			query.Before = cursor
+		}
+		return api.FeedQueryContext(ctx, query)
+	})
+}
+
+//Iterates over the posts liked by the current user, most recently liked first (requires
+//OAuth)
+//
+//query: Which media to return, as for LikedQuery
+func (api *InstagramAPI) LikedIterator(query MediaQuery) *Iterator {
+	return api.mediaIterator(nextMaxLikeID, func(ctx context.Context, cursor string) ([]Media, Pagination, error) {
+		if cursor != "" {
+			query.Before = cursor
+		}
+		return api.LikedQueryContext(ctx, query)
+	})
+}
+
+//Iterates over the users a user follows
+//
+//userID: a string representing the ID (not the username) of a given user
+func (api *InstagramAPI) UserFollowsIterator(userID string) *Iterator {
+	return api.userIterator(nextCursor, func(ctx context.Context, cursor string) ([]User, Pagination, error) {
+		return api.UserFollowsContext(ctx, userID, cursor)
+	})
+}
+
+//Iterates over the users following a user
+//
+//userID: a string representing the ID (not the username) of a given user
+func (api *InstagramAPI) UserFollowersIterator(userID string) *Iterator {
+	return api.userIterator(nextCursor, func(ctx context.Context, cursor string) ([]User, Pagination, error) {
+		return api.UserFollowersContext(ctx, userID, cursor)
+	})
+}
+
+//Iterates over the users who have asked to follow the authenticated user. Instagram
+//returns them as a single page, so the iterator never makes more than one request
+func (api *InstagramAPI) RequestedByIterator() *Iterator {
+	return api.userIterator(noNextPage, func(ctx context.Context, cursor string) ([]User, Pagination, error) {
+		return api.RequestedByContext(ctx)
+	})
+}
+
+//Iterates over the users matching a search. Instagram returns search results as a single
+//page, so the iterator never makes more than one request
+//
+//query: The description such as 'jack' or 'thomas' to search for
+//
+//max: (optional, default = 0) the number of users to return
+func (api *InstagramAPI) SearchUsersIterator(query string, max int) *Iterator {
+	return api.userIterator(noNextPage, func(ctx context.Context, cursor string) ([]User, Pagination, error) {
+		return api.SearchUsersContext(ctx, query, max)
+	})
+}
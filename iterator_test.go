@@ -0,0 +1,49 @@
+package instago
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestLikedIteratorPagesByLike(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("max_id") != "" {
+			t.Errorf("liked media requested with max_id=%s", query.Get("max_id"))
+		}
+		cursors = append(cursors, query.Get("max_like_id"))
+		switch query.Get("max_like_id") {
+		case "":
+			fmt.Fprint(w, `{"meta": {"code": 200}, "pagination": {"next_max_like_id": "L2"}, "data": [{"id": "3_1"}, {"id": "2_1"}]}`)
+		case "L2":
+			fmt.Fprint(w, `{"meta": {"code": 200}, "pagination": {}, "data": [{"id": "1_1"}]}`)
+		}
+	}))
+	defer server.Close()
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/"}
+
+	it := api.LikedIterator(MediaQuery{})
+	var ids []string
+	for it.Next(context.Background()) {
+		ids = append(ids, it.Media().ID)
+	}
+	if it.Err() != nil {
+		t.Fatal(it.Err())
+	}
+	if want := []string{"3_1", "2_1", "1_1"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("iterated over %v, want %v", ids, want)
+	}
+	if want := []string{"", "L2"}; !reflect.DeepEqual(cursors, want) {
+		t.Errorf("max_like_id sent %v, want %v", cursors, want)
+	}
+
+	media, _, err := api.Liked(0, "L2")
+	if err != nil || len(media) != 1 || media[0].ID != "1_1" {
+		t.Errorf("Liked(0, \"L2\") = %v, %v, want the second page", media, err)
+	}
+}
@@ -1,5 +1,7 @@
 package instago
 
+import "strconv"
+
 //The JSON type can be used when you do not directly want to parse JSON data into a Go
 //struct, or when you are dealing with object types that are unknown or constantly 
 //changing. The API uses this because a) The structure of some Instagram API requests adds
@@ -14,6 +16,16 @@ func JSONString(data interface{}) string {
 	return ""
 }
 
+//Check to see if an interface is a string or a number and return it as a string, or an
+//empty string if it is neither. Some IDs are numbers in some responses and strings in
+//others
+func JSONIDString(data interface{}) string {
+	if number, ok := data.(float64); ok {
+		return strconv.FormatFloat(number, 'f', -1, 64)
+	}
+	return JSONString(data)
+}
+
 //Check to see if an interface is an int and if it is not it will return 0
 func JSONInt(data interface{}) int {
 	//N.B. The encoding/json library assumes all numbers as float64 but most Instagram
@@ -29,6 +41,14 @@ func JSONFloat(data interface{}) float64 {
 	return 0
 }
 
+//Check to see if an interface is a bool and if it is not it will return false
+func JSONBool(data interface{}) bool {
+	if b, ok := data.(bool); ok {
+		return b
+	}
+	return false
+}
+
 //Checks to see if an interface is an array, and if not return an empty array
 func JSONArray(data interface{}) []interface{} {
 	if arr, ok := data.([]interface{}); ok {
@@ -71,6 +91,11 @@ func (json JSON) String(key string) string {
 	return JSONString(json[key])
 }
 
+//Utility wrapper around JSONIDString
+func (json JSON) IDString(key string) string {
+	return JSONIDString(json[key])
+}
+
 //Utility wrapper around JSONInt
 func (json JSON) Int(key string) int {
 	return JSONInt(json[key])
@@ -81,6 +106,11 @@ func (json JSON) Float(key string) float64 {
 	return JSONFloat(json[key])
 }
 
+//Utility wrapper around JSONBool
+func (json JSON) Bool(key string) bool {
+	return JSONBool(json[key])
+}
+
 //Utility wrapper around JSONArray
 func (json JSON) Array(key string) []interface{} {
 	return JSONArray(json[key])
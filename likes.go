@@ -0,0 +1,56 @@
+package instago
+
+import (
+	"context"
+	"net/http"
+)
+
+//Gets the users who have liked a media object
+//
+//mediaID: The ID of the media object
+func (api *InstagramAPI) MediaLikes(mediaID string) ([]User, error) {
+	return api.MediaLikesContext(context.Background(), mediaID)
+}
+
+//Same as MediaLikes, but the request is bound to ctx
+func (api *InstagramAPI) MediaLikesContext(ctx context.Context, mediaID string) ([]User, error) {
+	params := getEmptyMap()
+	result, err := api.DoRequestContext(ctx, "media/"+mediaID+"/likes", params)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, 0)
+	for _, user := range result.ObjectArray("data") {
+		users = append(users, UserFromAPI(user))
+	}
+	return users, nil
+}
+
+//Likes a media object as the authenticated user (requires OAuth with the likes scope)
+//
+//mediaID: The ID of the media object
+func (api *InstagramAPI) Like(mediaID string) error {
+	return api.LikeContext(context.Background(), mediaID)
+}
+
+//Same as Like, but the request is bound to ctx
+func (api *InstagramAPI) LikeContext(ctx context.Context, mediaID string) error {
+	params := getEmptyMap()
+	_, err := api.DoContext(ctx, http.MethodPost, "media/"+mediaID+"/likes", params)
+	return err
+}
+
+//Removes the authenticated user's like from a media object (requires OAuth with the likes
+//scope)
+//
+//mediaID: The ID of the media object
+func (api *InstagramAPI) Unlike(mediaID string) error {
+	return api.UnlikeContext(context.Background(), mediaID)
+}
+
+//Same as Unlike, but the request is bound to ctx
+func (api *InstagramAPI) UnlikeContext(ctx context.Context, mediaID string) error {
+	params := getEmptyMap()
+	_, err := api.DoContext(ctx, http.MethodDelete, "media/"+mediaID+"/likes", params)
+	return err
+}
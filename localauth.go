@@ -0,0 +1,160 @@
+package instago
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+//Settings for AuthorizeLocal. Every field is optional
+type LocalAuthOptions struct {
+	//Called with the authorization URL, typically to open it in a browser. If it is nil
+	//the URL is printed to Output for the user to open themselves
+	Open func(authURL string) error
+
+	//Where to print the authorization URL when Open is nil. Defaults to os.Stderr
+	Output io.Writer
+}
+
+//The page shown in the browser once the callback has been received
+const localAuthDone = "<html><body>Authorization complete, you can close this window.</body></html>"
+
+//AuthorizeLocal runs the whole authorization code flow for command-line and desktop tools.
+//It starts a temporary HTTP server on the loopback interface, sends the user to the
+//authorization URL, waits for Instagram to redirect back with a code (checking the state
+//parameter), exchanges the code and returns an InstagramAPI for the user.
+//
+//If RedirectURI is a loopback URL such as http://127.0.0.1:8000/callback the server
+//listens on that address and path, so it must be registered with Instagram as is. If
+//RedirectURI is empty a random port is picked and http://127.0.0.1:PORT/callback is used.
+//The config itself is never modified. AuthorizeLocal gives up when ctx is done
+func (config *OAuthConfig) AuthorizeLocal(ctx context.Context, options LocalAuthOptions) (*InstagramAPI, error) {
+	redirect, err := localRedirectURL(config.RedirectURI)
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, err
+	}
+	defer listener.Close()
+
+	local := *config
+	if local.RedirectURI == "" {
+		//Fill in the port that was picked by the system
+		redirect.Host = listener.Addr().String()
+		local.RedirectURI = redirect.String()
+	}
+
+	state, err := randomState()
+	if err != nil {
+		return nil, err
+	}
+
+	type callback struct {
+		code string
+		err  error
+	}
+	results := make(chan callback, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		//Requests that don't carry our state (a stray browser request, /favicon.ico, another
+		//process probing the port) are turned down without ending the flow
+		if query.Get("state") != state {
+			http.Error(w, "instago: state mismatch in authorization callback", http.StatusBadRequest)
+			return
+		}
+		var result callback
+		switch {
+		case query.Get("error") != "":
+			result.err = fmt.Errorf("instago: authorization failed: %s: %s", query.Get("error_reason"), query.Get("error_description"))
+		case query.Get("code") == "":
+			http.Error(w, "instago: no code in authorization callback", http.StatusBadRequest)
+			return
+		default:
+			result.code = query.Get("code")
+		}
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			io.WriteString(w, localAuthDone)
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	defer server.Close()
+
+	authURL := local.AuthCodeURL(state)
+	if options.Open != nil {
+		if err := options.Open(authURL); err != nil {
+			return nil, err
+		}
+	} else {
+		output := options.Output
+		if output == nil {
+			output = os.Stderr
+		}
+		fmt.Fprintf(output, "Open this URL in your browser to authorize the application:\n%s\n", authURL)
+	}
+
+	var result callback
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result = <-results:
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	token, err := local.ExchangeContext(ctx, result.code)
+	if err != nil {
+		return nil, err
+	}
+	return local.Client(token), nil
+}
+
+//Works out where the callback server should listen from the configured RedirectURI
+func localRedirectURL(redirectURI string) (*url.URL, error) {
+	if redirectURI == "" {
+		return &url.URL{Scheme: "http", Host: "127.0.0.1:0", Path: "/callback"}, nil
+	}
+	u, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" {
+		return nil, fmt.Errorf("instago: redirect URI %q must use http to be served locally", redirectURI)
+	}
+	host := u.Hostname()
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return nil, fmt.Errorf("instago: redirect URI %q is not on the loopback interface", redirectURI)
+	}
+	if u.Port() == "" {
+		u.Host = net.JoinHostPort(host, "80")
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u, nil
+}
+
+//Generates an unguessable value for the state parameter
+func randomState() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,74 @@
+package instago
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+//Runs AuthorizeLocal against a fake token endpoint. callbacks is called with the redirect
+//URI and state once the flow has started, and makes the requests a browser would
+func authorizeLocal(t *testing.T, callbacks func(redirectURI, state string)) (*InstagramAPI, error) {
+	tokens := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("code") != "the-code" {
+			t.Errorf("exchanged code %q, want the-code", r.Form.Get("code"))
+		}
+		w.Write([]byte(`{"access_token": "token", "user": {"id": "1", "username": "someone"}}`))
+	}))
+	defer tokens.Close()
+	config := &OAuthConfig{ClientID: "id", ClientSecret: "secret", TokenURL: tokens.URL}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return config.AuthorizeLocal(ctx, LocalAuthOptions{Open: func(authURL string) error {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			return err
+		}
+		go callbacks(u.Query().Get("redirect_uri"), u.Query().Get("state"))
+		return nil
+	}})
+}
+
+func getStatus(t *testing.T, rawURL string) int {
+	resp, err := http.Get(rawURL)
+	if err != nil {
+		t.Error(err)
+		return 0
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestAuthorizeLocalIgnoresStrayRequests(t *testing.T) {
+	api, err := authorizeLocal(t, func(redirectURI, state string) {
+		for _, query := range []string{"", "?code=forged&state=wrong", "?error=access_denied&state=wrong", "?state=" + state} {
+			if status := getStatus(t, redirectURI+query); status != http.StatusBadRequest {
+				t.Errorf("callback%s answered with %d, want 400", query, status)
+			}
+		}
+		if status := getStatus(t, redirectURI+"?code=the-code&state="+state); status != http.StatusOK {
+			t.Errorf("real callback answered with %d, want 200", status)
+		}
+	})
+	if err != nil {
+		t.Fatalf("AuthorizeLocal: %v", err)
+	}
+	if api.AccessToken != "token" || api.ClientSecret != "secret" {
+		t.Errorf("got client %+v", api)
+	}
+}
+
+func TestAuthorizeLocalDenied(t *testing.T) {
+	_, err := authorizeLocal(t, func(redirectURI, state string) {
+		getStatus(t, redirectURI+"?error=access_denied&error_reason=user_denied&error_description=The+user+denied+your+request&state="+state)
+	})
+	if err == nil || !strings.Contains(err.Error(), "user_denied") {
+		t.Errorf("AuthorizeLocal returned %v, want the user_denied error", err)
+	}
+}
@@ -1,14 +1,25 @@
 package instago
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 //Gets basic information such as name and coordinates for a location
 //
 //locationId: The id of a location to lookup
-func (api InstagramAPI) Location(locationId string) Location {
+func (api *InstagramAPI) Location(locationId string) (Location, error) {
+	return api.LocationContext(context.Background(), locationId)
+}
+
+//Same as Location, but the request is bound to ctx
+func (api *InstagramAPI) LocationContext(ctx context.Context, locationId string) (Location, error) {
 	params := getEmptyMap()
-	response := api.DoRequest("locations/"+locationId, params)
-	return LocationFromAPI(response.Object("data"))
+	response, err := api.DoRequestContext(ctx, "locations/"+locationId, params)
+	if err != nil {
+		return Location{}, err
+	}
+	return LocationFromAPI(response.Object("data")), nil
 }
 
 //Gets media posted from that location
@@ -18,8 +29,28 @@ func (api InstagramAPI) Location(locationId string) Location {
 //beforePost: (optional = "") posts before this ID
 //
 //afterPost: (optional = "") posts after this ID
-func (api InstagramAPI) LocationPosts(locationId, beforePost, afterPost string) ([]Media, Pagination, error) {
-	return api.GenericMediaListRequest("locations/"+locationId+"/media/recent", beforePost, afterPost, 0)
+func (api *InstagramAPI) LocationPosts(locationId, beforePost, afterPost string) ([]Media, Pagination, error) {
+	return api.LocationPostsContext(context.Background(), locationId, beforePost, afterPost)
+}
+
+//Same as LocationPosts, but the request is bound to ctx
+func (api *InstagramAPI) LocationPostsContext(ctx context.Context, locationId, beforePost, afterPost string) ([]Media, Pagination, error) {
+	return api.LocationPostsQueryContext(ctx, locationId, MediaQuery{Before: beforePost, After: afterPost})
+}
+
+//Same as LocationPosts, but takes a MediaQuery so that you can, for instance, get the
+//media posted at a venue during an event
+//
+//locationId: The id of the location
+//
+//query: Which media to return
+func (api *InstagramAPI) LocationPostsQuery(locationId string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.LocationPostsQueryContext(context.Background(), locationId, query)
+}
+
+//Same as LocationPostsQuery, but the request is bound to ctx
+func (api *InstagramAPI) LocationPostsQueryContext(ctx context.Context, locationId string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.GenericMediaListQueryContext(ctx, "locations/"+locationId+"/media/recent", query)
 }
 
 //Gets a list of locations near a give latitude/longitude within a certain distance
@@ -29,21 +60,80 @@ func (api InstagramAPI) LocationPosts(locationId, beforePost, afterPost string)
 //long: The longitude to search near
 //
 //distance: (optional = 0) The number of meters to search within
-func (api InstagramAPI) LocationsNear(lat, long, distance float64) ([]Location, Pagination, error) {
+func (api *InstagramAPI) LocationsNear(lat, long, distance float64) ([]Location, Pagination, error) {
+	return api.LocationsNearContext(context.Background(), lat, long, distance)
+}
+
+//Same as LocationsNear, but the request is bound to ctx
+func (api *InstagramAPI) LocationsNearContext(ctx context.Context, lat, long, distance float64) ([]Location, Pagination, error) {
 	params := getEmptyMap()
 	if distance > 0 {
 		params["distance"] = fmt.Sprintf("%f", distance)
 	}
 	params["lat"] = fmt.Sprintf("%f", lat)
 	params["lng"] = fmt.Sprintf("%f", long)
-	results := api.DoRequest("locations/search", params)
+	return api.locationSearch(ctx, params)
+}
+
+//Gets the Instagram locations that match a Foursquare venue. Both current (v2, such as
+//4b9f1d0cf964a520e22a37e3) and legacy numeric venue IDs are accepted
+//
+//venueID: The ID of the venue on Foursquare
+func (api *InstagramAPI) LocationsByFoursquareID(venueID string) ([]Location, Pagination, error) {
+	return api.LocationsByFoursquareIDContext(context.Background(), venueID)
+}
+
+//Same as LocationsByFoursquareID, but the request is bound to ctx
+func (api *InstagramAPI) LocationsByFoursquareIDContext(ctx context.Context, venueID string) ([]Location, Pagination, error) {
+	params := getEmptyMap()
+	if isNumeric(venueID) {
+		params["foursquare_id"] = venueID
+	} else {
+		params["foursquare_v2_id"] = venueID
+	}
+	return api.locationSearch(ctx, params)
+}
+
+//Gets the Instagram locations that match a Facebook Place
+//
+//placeID: The ID of the place on Facebook
+func (api *InstagramAPI) LocationsByFacebookPlacesID(placeID string) ([]Location, Pagination, error) {
+	return api.LocationsByFacebookPlacesIDContext(context.Background(), placeID)
+}
+
+//Same as LocationsByFacebookPlacesID, but the request is bound to ctx
+func (api *InstagramAPI) LocationsByFacebookPlacesIDContext(ctx context.Context, placeID string) ([]Location, Pagination, error) {
+	params := getEmptyMap()
+	params["facebook_places_id"] = placeID
+	return api.locationSearch(ctx, params)
+}
+
+//LocationsNear, LocationsByFoursquareID and LocationsByFacebookPlacesID all search
+//locations/search, only with different parameters
+func (api *InstagramAPI) locationSearch(ctx context.Context, params map[string]string) ([]Location, Pagination, error) {
+	results, err := api.DoRequestContext(ctx, "locations/search", params)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
 	data := results.ObjectArray("data")
 	locations := make([]Location, 0)
 	for _, loc := range data {
 		locations = append(locations, LocationFromAPI(loc))
 	}
 	pagination := PaginationFromAPI(results.Object("pagination"))
-	err := api.ErrorFromAPI(results)
 
-	return locations, pagination, err
+	return locations, pagination, nil
+}
+
+//Reports whether s is made up only of digits
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
 }
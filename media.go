@@ -1,19 +1,104 @@
 package instago
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 //Gets details for media with the given ID
 //
 //mediaId: A string representing the ID of the media to get info on
-func (api InstagramAPI) Media(mediaId string) Media {
+func (api *InstagramAPI) Media(mediaId string) (Media, error) {
+	return api.MediaContext(context.Background(), mediaId)
+}
+
+//Same as Media, but the request is bound to ctx
+func (api *InstagramAPI) MediaContext(ctx context.Context, mediaId string) (Media, error) {
+	params := getEmptyMap()
+	response, err := api.DoRequestContext(ctx, "media/"+mediaId, params)
+	if err != nil {
+		return Media{}, err
+	}
+	return MediaFromAPI(response.Object("data")), nil
+}
+
+//Gets details for the media with the given shortcode, which is the part of a post's link
+//after /p/ (such as AbC123 in https://instagram.com/p/AbC123/)
+//
+//code: The shortcode of the media
+func (api *InstagramAPI) MediaByShortcode(code string) (Media, error) {
+	return api.MediaByShortcodeContext(context.Background(), code)
+}
+
+//Same as MediaByShortcode, but the request is bound to ctx
+func (api *InstagramAPI) MediaByShortcodeContext(ctx context.Context, code string) (Media, error) {
 	params := getEmptyMap()
-	response := api.DoRequest("media/"+mediaId, params)
-	return MediaFromAPI(response.Object("data"))
+	response, err := api.DoRequestContext(ctx, "media/shortcode/"+code, params)
+	if err != nil {
+		return Media{}, err
+	}
+	return MediaFromAPI(response.Object("data")), nil
+}
+
+//Gets details for the media a link to a post points at, see ParseMediaURL for the links
+//that are understood
+//
+//link: The link to the post, such as https://www.instagram.com/p/AbC123/
+func (api *InstagramAPI) MediaByURL(link string) (Media, error) {
+	return api.MediaByURLContext(context.Background(), link)
+}
+
+//Same as MediaByURL, but the request is bound to ctx
+func (api *InstagramAPI) MediaByURLContext(ctx context.Context, link string) (Media, error) {
+	code, err := ParseMediaURL(link)
+	if err != nil {
+		return Media{}, err
+	}
+	return api.MediaByShortcodeContext(ctx, code)
+}
+
+//Pulls the shortcode out of a link to a post. Links may point at instagram.com (with or
+//without www) or instagr.am, may leave out the scheme, may have a trailing slash, a query
+//string or a fragment, and may use the /p/, /tv/ or /reel/ form
+//
+//link: The link to the post, such as https://www.instagram.com/p/AbC123/?igshid=xyz
+func ParseMediaURL(link string) (string, error) {
+	trimmed := strings.TrimSpace(link)
+	if !strings.Contains(trimmed, "://") {
+		trimmed = "https://" + trimmed
+	}
+	u, err := url.Parse(trimmed)
+	if err != nil {
+		return "", err
+	}
+	switch strings.ToLower(strings.TrimPrefix(u.Hostname(), "www.")) {
+	case "instagram.com", "instagr.am":
+	default:
+		return "", fmt.Errorf("instago: %q is not an Instagram link", link)
+	}
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i := 0; i+1 < len(segments); i++ {
+		switch segments[i] {
+		case "p", "tv", "reel":
+			if segments[i+1] != "" {
+				return segments[i+1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("instago: %q is not a link to a post", link)
 }
 
 //Gets a list of popular media at the moment
-func (api InstagramAPI) Popular() ([]Media, Pagination, error) {
-	return api.GenericMediaListRequest("media/popular", "", "", 0)
+func (api *InstagramAPI) Popular() ([]Media, Pagination, error) {
+	return api.PopularContext(context.Background())
+}
+
+//Same as Popular, but the request is bound to ctx
+func (api *InstagramAPI) PopularContext(ctx context.Context) ([]Media, Pagination, error) {
+	return api.GenericMediaListRequestContext(ctx, "media/popular", "", "", 0)
 }
 
 //Gets a list of media posted from a certain location recently.
@@ -24,22 +109,32 @@ func (api InstagramAPI) Popular() ([]Media, Pagination, error) {
 //long: The longitude to search near
 //
 //distance: (optional = 0) The number of meters to search within
-func (api InstagramAPI) LocationSearch(lat, lng, distance float64) ([]Media, Pagination, error) {
+func (api *InstagramAPI) LocationSearch(lat, lng, distance float64) ([]Media, Pagination, error) {
+	return api.LocationSearchContext(context.Background(), lat, lng, distance)
+}
+
+//Same as LocationSearch, but the request is bound to ctx
+func (api *InstagramAPI) LocationSearchContext(ctx context.Context, lat, lng, distance float64) ([]Media, Pagination, error) {
+	return api.LocationSearchQueryContext(ctx, lat, lng, MediaQuery{Distance: distance})
+}
+
+//Same as LocationSearch, but takes a MediaQuery so that the media can also be narrowed
+//down by time. The search radius is query.Distance
+//
+//lat: The latitude to search near
+//
+//lng: The longitude to search near
+//
+//query: Which media to return
+func (api *InstagramAPI) LocationSearchQuery(lat, lng float64, query MediaQuery) ([]Media, Pagination, error) {
+	return api.LocationSearchQueryContext(context.Background(), lat, lng, query)
+}
+
+//Same as LocationSearchQuery, but the request is bound to ctx
+func (api *InstagramAPI) LocationSearchQueryContext(ctx context.Context, lat, lng float64, query MediaQuery) ([]Media, Pagination, error) {
 	//Unfortunately I couldn't use GenericMediaListRequest because it takes in location
-	params := getEmptyMap()
-	if distance > 0 {
-		params["distance"] = fmt.Sprintf("%f", distance)
-	}
+	params := query.params(false)
 	params["lat"] = fmt.Sprintf("%f", lat)
 	params["lng"] = fmt.Sprintf("%f", lng)
-	results := api.DoRequest("media/search", params)
-	data := results.ObjectArray("data")
-	media_objects := make([]Media, 0)
-	for _, media := range data {
-		media_objects = append(media_objects, MediaFromAPI(media))
-	}
-	pagination := PaginationFromAPI(results.Object("pagination"))
-	err := api.ErrorFromAPI(results)
-
-	return media_objects, pagination, err
+	return api.mediaList(ctx, "media/search", params)
 }
@@ -0,0 +1,192 @@
+package instago
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+//A permission that an application can ask a user for when they authorize it
+type Scope string
+
+//The scopes Instagram knows about. Every application gets ScopeBasic
+const (
+	ScopeBasic         Scope = "basic"
+	ScopeComments      Scope = "comments"
+	ScopeRelationships Scope = "relationships"
+	ScopeLikes         Scope = "likes"
+	ScopePublicContent Scope = "public_content"
+	ScopeFollowerList  Scope = "follower_list"
+)
+
+//Where Instagram's OAuth endpoints live
+const (
+	DefaultAuthURL  = "https://api.instagram.com/oauth/authorize/"
+	DefaultTokenURL = "https://api.instagram.com/oauth/access_token"
+)
+
+//OAuthConfig describes your application as registered on http://instagram.com/developer
+//and walks a user through authorizing it:
+//
+//1. Send the user to AuthCodeURL (or ImplicitURL for client-side applications)
+//
+//2. Instagram redirects them back to RedirectURI with a code (or with an access token in
+//the fragment for the implicit flow)
+//
+//3. Exchange the code for a Token, and use Client to get an InstagramAPI for that user
+//
+//AuthURL, TokenURL and HTTPClient are optional; if they are empty DefaultAuthURL,
+//DefaultTokenURL and http.DefaultClient are used
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	Scopes       []Scope
+	AuthURL      string
+	TokenURL     string
+	HTTPClient   *http.Client
+}
+
+//The result of a successful authorization: the access token along with the user who
+//granted it
+type Token struct {
+	AccessToken string
+	User        User
+}
+
+//Returns the URL to send the user to for the server-side (authorization code) flow. state
+//is sent back untouched with the code and should be checked to protect against CSRF
+func (config *OAuthConfig) AuthCodeURL(state string) string {
+	return config.authURL("code", state)
+}
+
+//Returns the URL to send the user to for the client-side (implicit) flow, where the access
+//token comes back in the fragment of the redirect. See AccessTokenFromFragment
+func (config *OAuthConfig) ImplicitURL(state string) string {
+	return config.authURL("token", state)
+}
+
+//Builds the authorization URL for the given response_type
+func (config *OAuthConfig) authURL(responseType, state string) string {
+	authURL := config.AuthURL
+	if authURL == "" {
+		authURL = DefaultAuthURL
+	}
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("client_id", config.ClientID)
+	q.Set("redirect_uri", config.RedirectURI)
+	q.Set("response_type", responseType)
+	if len(config.Scopes) > 0 {
+		scopes := make([]string, 0, len(config.Scopes))
+		for _, scope := range config.Scopes {
+			scopes = append(scopes, string(scope))
+		}
+		q.Set("scope", strings.Join(scopes, " "))
+	}
+	if state != "" {
+		q.Set("state", state)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+//Trades the code Instagram sent to the RedirectURI for an access token
+//
+//code: the code parameter of the redirect
+func (config *OAuthConfig) Exchange(code string) (Token, error) {
+	return config.ExchangeContext(context.Background(), code)
+}
+
+//Same as Exchange, but the request is bound to ctx
+func (config *OAuthConfig) ExchangeContext(ctx context.Context, code string) (Token, error) {
+	form := url.Values{}
+	form.Set("client_id", config.ClientID)
+	form.Set("client_secret", config.ClientSecret)
+	form.Set("grant_type", "authorization_code")
+	form.Set("redirect_uri", config.RedirectURI)
+	form.Set("code", code)
+
+	tokenURL := config.TokenURL
+	if tokenURL == "" {
+		tokenURL = DefaultTokenURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return Token{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := config.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return Token{}, networkError(ctx, err)
+	}
+	defer resp.Body.Close()
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return Token{}, networkError(ctx, err)
+	}
+
+	//The token endpoint reports errors at the top level rather than in a meta block
+	var body JSON
+	if json.Unmarshal(contents, &body) == nil {
+		if apiErr := apiErrorFromMeta(body); apiErr != nil {
+			apiErr.StatusCode = resp.StatusCode
+			return Token{}, apiErr
+		}
+	}
+	result, err := parseResponse(resp, contents)
+	if err != nil {
+		return Token{}, err
+	}
+	token := Token{
+		AccessToken: result.String("access_token"),
+		User:        UserFromAPI(result.Object("user")),
+	}
+	if token.AccessToken == "" {
+		return Token{}, fmt.Errorf("%w: no access_token in response", ErrMalformedJSON)
+	}
+	return token, nil
+}
+
+//Returns an InstagramAPI that makes requests on behalf of the user who granted token.
+//The ClientSecret is passed on too, so that requests are signed
+func (config *OAuthConfig) Client(token Token) *InstagramAPI {
+	return &InstagramAPI{
+		ClientID:     config.ClientID,
+		AccessToken:  token.AccessToken,
+		ClientSecret: config.ClientSecret,
+		HTTPClient:   config.HTTPClient,
+	}
+}
+
+//Pulls the access token out of the URL Instagram redirected to at the end of the implicit
+//flow (http://your-redirect-uri#access_token=ACCESS-TOKEN)
+func AccessTokenFromFragment(redirectURL string) (string, error) {
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return "", err
+	}
+	values, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return "", err
+	}
+	if token := values.Get("access_token"); token != "" {
+		return token, nil
+	}
+	if reason := u.Query().Get("error_description"); reason != "" {
+		return "", fmt.Errorf("instago: authorization failed: %s", reason)
+	}
+	return "", fmt.Errorf("instago: no access_token in %q", redirectURL)
+}
@@ -0,0 +1,103 @@
+package instago
+
+import (
+	"context"
+	"errors"
+	"net/http"
+)
+
+//Returned by the FetchNext methods when the Pagination has no NextUrl
+var ErrNoNextPage = errors.New("instago: no next page")
+
+//The parameters Instagram puts in next_url that the client adds itself, so that the
+//configured credentials (and a fresh signature) are used instead of the ones in the URL
+var credentialParams = []string{"access_token", "client_id", "client_secret", "sig"}
+
+//Requests Pagination.NextUrl through the configured BaseURL, credentials and signing
+func (api *InstagramAPI) fetchNext(ctx context.Context, p Pagination) (JSON, error) {
+	if p.NextUrl == "" {
+		return nil, ErrNoNextPage
+	}
+	endpoint, query, err := api.endpointFromURL(p.NextUrl)
+	if err != nil {
+		return nil, err
+	}
+	//Endpoints that only take a client_id hand out next URLs without an access_token
+	creds := userCredentials
+	if query.Get("access_token") == "" && query.Get("client_id") != "" {
+		creds = clientCredentials
+	}
+	for _, key := range credentialParams {
+		query.Del(key)
+	}
+	params := getEmptyMap()
+	for key := range query {
+		params[key] = query.Get(key)
+	}
+	return api.do(ctx, http.MethodGet, endpoint, params, creds)
+}
+
+//Gets the next page of a media list, such as the one returned by TagRecent or Feed, by
+//following Pagination.NextUrl. The request goes to the configured BaseURL with the
+//configured credentials rather than the ones embedded in NextUrl. Returns ErrNoNextPage
+//on the last page
+//
+//p: The Pagination returned with the previous page
+func (api *InstagramAPI) FetchNextMedia(p Pagination) ([]Media, Pagination, error) {
+	return api.FetchNextMediaContext(context.Background(), p)
+}
+
+//Same as FetchNextMedia, but the request is bound to ctx
+func (api *InstagramAPI) FetchNextMediaContext(ctx context.Context, p Pagination) ([]Media, Pagination, error) {
+	result, err := api.fetchNext(ctx, p)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+	media := make([]Media, 0)
+	for _, m := range result.ObjectArray("data") {
+		media = append(media, MediaFromAPI(m))
+	}
+	return media, PaginationFromAPI(result.Object("pagination")), nil
+}
+
+//Gets the next page of a user list, such as the one returned by UserFollowers, by
+//following Pagination.NextUrl. See FetchNextMedia
+//
+//p: The Pagination returned with the previous page
+func (api *InstagramAPI) FetchNextUsers(p Pagination) ([]User, Pagination, error) {
+	return api.FetchNextUsersContext(context.Background(), p)
+}
+
+//Same as FetchNextUsers, but the request is bound to ctx
+func (api *InstagramAPI) FetchNextUsersContext(ctx context.Context, p Pagination) ([]User, Pagination, error) {
+	result, err := api.fetchNext(ctx, p)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+	users := make([]User, 0)
+	for _, user := range result.ObjectArray("data") {
+		users = append(users, UserFromAPI(user))
+	}
+	return users, PaginationFromAPI(result.Object("pagination")), nil
+}
+
+//Gets the next page of a tag list, such as the one returned by TagSearch, by following
+//Pagination.NextUrl. See FetchNextMedia
+//
+//p: The Pagination returned with the previous page
+func (api *InstagramAPI) FetchNextTags(p Pagination) ([]Tag, Pagination, error) {
+	return api.FetchNextTagsContext(context.Background(), p)
+}
+
+//Same as FetchNextTags, but the request is bound to ctx
+func (api *InstagramAPI) FetchNextTagsContext(ctx context.Context, p Pagination) ([]Tag, Pagination, error) {
+	result, err := api.fetchNext(ctx, p)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+	tags := make([]Tag, 0)
+	for _, tag := range result.ObjectArray("data") {
+		tags = append(tags, tagObject(tag))
+	}
+	return tags, PaginationFromAPI(result.Object("pagination")), nil
+}
@@ -0,0 +1,52 @@
+package instago
+
+import (
+	"fmt"
+	"time"
+)
+
+//MediaQuery narrows down the media returned by the ...Query methods. Every field is
+//optional and the zero value asks for the most recent page
+//
+//Before and After bound the media IDs (or tag IDs for TagRecentQuery, see
+//Pagination.NextMaxTagId and Pagination.MinTagId). MinTime and MaxTime bound when the
+//media was posted; Instagram honours them for media search, location and user media.
+//Count is the greatest number of media objects to return and Distance (in meters) is the
+//radius used by LocationSearchQuery
+type MediaQuery struct {
+	Before   string
+	After    string
+	MinTime  time.Time
+	MaxTime  time.Time
+	Count    int
+	Distance float64
+}
+
+//Turns the query into request parameters. tagIDs selects the max_tag_id/min_tag_id
+//parameters used by tag endpoints instead of max_id/min_id
+func (query MediaQuery) params(tagIDs bool) map[string]string {
+	params := getEmptyMap()
+	maxID, minID := "max_id", "min_id"
+	if tagIDs {
+		maxID, minID = "max_tag_id", "min_tag_id"
+	}
+	if query.Before != "" {
+		params[maxID] = query.Before
+	}
+	if query.After != "" {
+		params[minID] = query.After
+	}
+	if !query.MinTime.IsZero() {
+		params["min_timestamp"] = fmt.Sprintf("%d", query.MinTime.Unix())
+	}
+	if !query.MaxTime.IsZero() {
+		params["max_timestamp"] = fmt.Sprintf("%d", query.MaxTime.Unix())
+	}
+	if query.Count > 0 {
+		params["count"] = fmt.Sprintf("%d", query.Count)
+	}
+	if query.Distance > 0 {
+		params["distance"] = fmt.Sprintf("%f", query.Distance)
+	}
+	return params
+}
@@ -0,0 +1,169 @@
+package instago
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+//A RateLimiter keeps a client within Instagram's hourly quota. It learns the quota from
+//the X-Ratelimit-Limit and X-Ratelimit-Remaining headers of every response and spreads
+//the remaining budget evenly over what is left of the window, so requests slow down
+//gradually instead of failing with OAuthRateLimitException. When the budget is used up
+//Wait blocks until the window rolls over. A RateLimiter is safe to share between
+//goroutines and clients that use the same credentials
+type RateLimiter struct {
+	//The length of the window Instagram counts requests over. If it is zero an hour is
+	//assumed
+	Window time.Duration
+
+	mu          sync.Mutex
+	known       bool
+	budget      RateLimit
+	windowStart time.Time
+	next        time.Time
+	//The Remaining value of the last response, before the requests still in flight were
+	//taken off it
+	reported int
+	//Requests that have been let through by Wait but haven't been answered yet
+	inFlight int
+	//Slots handed out from the next window while the current one was used up
+	borrowed int
+}
+
+//Creates a RateLimiter for Instagram's hourly window
+func NewRateLimiter() *RateLimiter {
+	return &RateLimiter{Window: time.Hour}
+}
+
+//Blocks until another request fits in the budget, or until ctx is done in which case the
+//context's error is returned. Until the first response has been seen the budget is
+//unknown and Wait returns straight away
+func (limiter *RateLimiter) Wait(ctx context.Context) error {
+	limiter.mu.Lock()
+	limiter.inFlight++
+	now := time.Now()
+	reset := limiter.windowStart.Add(limiter.window())
+	if limiter.known && limiter.budget.Limit <= 0 && !now.Before(reset) {
+		//Exhaust held requests back without knowing the quota; now that its pause is over
+		//the budget is unknown again until the next response
+		limiter.known = false
+	}
+	if !limiter.known {
+		limiter.mu.Unlock()
+		if err := ctx.Err(); err != nil {
+			limiter.done()
+			return err
+		}
+		return nil
+	}
+
+	if !now.Before(reset) {
+		//The window has rolled over since we last heard from Instagram. Requests queued
+		//up while it was used up already hold slots in the new one
+		limiter.budget.Remaining = limiter.budget.Limit - limiter.borrowed
+		limiter.borrowed = 0
+		limiter.windowStart = now
+		reset = now.Add(limiter.window())
+	}
+
+	var start time.Time
+	if limiter.budget.Remaining <= 0 {
+		//Take a slot from the next window, so that the requests waiting for it are spread
+		//over it rather than all sent the moment it opens
+		start = reset
+		if limiter.budget.Limit > 0 {
+			if limiter.next.After(start) {
+				start = limiter.next
+			}
+			limiter.next = start.Add(limiter.window() / time.Duration(limiter.budget.Limit))
+			limiter.borrowed++
+		}
+	} else {
+		interval := reset.Sub(now) / time.Duration(limiter.budget.Remaining)
+		start = limiter.next
+		if start.Before(now) {
+			start = now
+		}
+		limiter.next = start.Add(interval)
+		limiter.budget.Remaining--
+	}
+	limiter.mu.Unlock()
+
+	if err := sleepContext(ctx, time.Until(start)); err != nil {
+		limiter.done()
+		return err
+	}
+	return nil
+}
+
+//Records that a request let through by Wait has been answered (or given up on). Safe to
+//call on a nil RateLimiter
+func (limiter *RateLimiter) done() {
+	if limiter == nil {
+		return
+	}
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	if limiter.inFlight > 0 {
+		limiter.inFlight--
+	}
+}
+
+//Records the rate limit reported by a response. Responses without rate limit headers
+//(Limit is 0) are ignored. Requests that are still in flight aren't counted in the
+//reported Remaining yet, so they are taken off it
+func (limiter *RateLimiter) Update(budget RateLimit) {
+	if budget.Limit <= 0 {
+		return
+	}
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	now := time.Now()
+	elapsed := !now.Before(limiter.windowStart.Add(limiter.window()))
+	//Concurrent responses can arrive out of order, so more budget than last time only
+	//means a new window once the current one is over
+	if !limiter.known || (elapsed && budget.Remaining > limiter.reported) {
+		limiter.windowStart = now
+		limiter.borrowed = 0
+	}
+	limiter.known = true
+	limiter.reported = budget.Remaining
+	remaining := budget.Remaining - limiter.inFlight
+	if remaining < 0 {
+		remaining = 0
+	}
+	limiter.budget = RateLimit{Limit: budget.Limit, Remaining: remaining}
+}
+
+//Marks the budget as used up, for instance after Instagram answered with
+//OAuthRateLimitException. If retryAfter (the response's Retry-After) is set requests are
+//held back until it has passed; otherwise, until the current window is over, or for a
+//whole Window if the quota isn't known yet
+func (limiter *RateLimiter) Exhaust(retryAfter time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	now := time.Now()
+	if retryAfter > 0 {
+		limiter.windowStart = now.Add(retryAfter - limiter.window())
+	} else if !limiter.known {
+		limiter.windowStart = now
+	}
+	limiter.known = true
+	limiter.reported = 0
+	limiter.budget.Remaining = 0
+}
+
+//Returns the most recently known budget
+func (limiter *RateLimiter) Budget() RateLimit {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	return limiter.budget
+}
+
+func (limiter *RateLimiter) window() time.Duration {
+	if limiter.Window > 0 {
+		return limiter.Window
+	}
+	return time.Hour
+}
@@ -0,0 +1,161 @@
+package instago
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterUnknownBudget(t *testing.T) {
+	limiter := NewRateLimiter()
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("Wait blocked for %v before any budget was known", elapsed)
+	}
+}
+
+func TestRateLimiterInFlight(t *testing.T) {
+	limiter := &RateLimiter{Window: 4 * time.Second}
+	limiter.Update(RateLimit{Limit: 5000, Remaining: 4000})
+	windowStart := limiter.windowStart
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Wait(context.Background())
+		}()
+	}
+	wg.Wait()
+	//The first of the five requests is answered; the other four are still in flight
+	limiter.done()
+	limiter.Update(RateLimit{Limit: 5000, Remaining: 3999})
+
+	if !limiter.windowStart.Equal(windowStart) {
+		t.Error("requests in flight were taken for the window rolling over")
+	}
+	if budget := limiter.Budget(); budget.Remaining != 3995 {
+		t.Errorf("Remaining = %d, want 3995", budget.Remaining)
+	}
+}
+
+func TestRateLimiterOutOfOrder(t *testing.T) {
+	limiter := &RateLimiter{Window: time.Hour}
+	limiter.Update(RateLimit{Limit: 100, Remaining: 50})
+	windowStart := limiter.windowStart
+	time.Sleep(time.Millisecond)
+	limiter.Update(RateLimit{Limit: 100, Remaining: 52})
+	if !limiter.windowStart.Equal(windowStart) {
+		t.Error("an out of order response moved the window")
+	}
+}
+
+func TestRateLimiterRollover(t *testing.T) {
+	limiter := &RateLimiter{Window: 20 * time.Millisecond}
+	limiter.Update(RateLimit{Limit: 100, Remaining: 10})
+	windowStart := limiter.windowStart
+	time.Sleep(30 * time.Millisecond)
+	limiter.Update(RateLimit{Limit: 100, Remaining: 99})
+	if limiter.windowStart.Equal(windowStart) {
+		t.Error("the window didn't roll over once it was over and budget came back")
+	}
+}
+
+func TestRateLimiterRemainingNotNegative(t *testing.T) {
+	limiter := &RateLimiter{Window: time.Hour}
+	limiter.inFlight = 5
+	limiter.Update(RateLimit{Limit: 100, Remaining: 2})
+	if budget := limiter.Budget(); budget.Remaining != 0 {
+		t.Errorf("Remaining = %d, want 0", budget.Remaining)
+	}
+}
+
+func TestRateLimiterSpreadsNextWindow(t *testing.T) {
+	limiter := &RateLimiter{Window: 200 * time.Millisecond}
+	limiter.Update(RateLimit{Limit: 4, Remaining: 0})
+
+	begin := time.Now()
+	var mu sync.Mutex
+	var sent []time.Duration
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			limiter.Wait(context.Background())
+			mu.Lock()
+			sent = append(sent, time.Since(begin))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(sent, func(i, j int) bool { return sent[i] < sent[j] })
+	if sent[0] < 190*time.Millisecond {
+		t.Errorf("a request was let through at %v, before the window was over", sent[0])
+	}
+	for i := 1; i < len(sent); i++ {
+		if gap := sent[i] - sent[i-1]; gap < 30*time.Millisecond {
+			t.Errorf("requests sent %v apart, want them spread over the window: %v", gap, sent)
+		}
+	}
+}
+
+func TestRateLimiterExhaustRetryAfter(t *testing.T) {
+	limiter := &RateLimiter{Window: time.Hour}
+	limiter.Exhaust(30 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := limiter.Wait(ctx); err != nil {
+		t.Fatalf("Wait: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 25*time.Millisecond {
+		t.Errorf("Wait returned after %v, before Retry-After had passed", elapsed)
+	}
+	//The quota is still unknown, so nothing holds the next request back
+	start = time.Now()
+	limiter.Wait(ctx)
+	if elapsed := time.Since(start); elapsed > 20*time.Millisecond {
+		t.Errorf("Wait blocked for %v after the Retry-After pause", elapsed)
+	}
+}
+
+func TestRateLimiterExhaustFromResponse(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {}}`))
+	}))
+	defer server.Close()
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/", Limiter: NewRateLimiter()}
+
+	if _, err := api.DoRequest("users/self", nil); !IsRateLimited(err) {
+		t.Fatalf("first request returned %v, want a rate limit error", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	start := time.Now()
+	if _, err := api.DoRequestContext(ctx, "users/self", nil); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("second request sent after %v, want it held back for the Retry-After", elapsed)
+	}
+}
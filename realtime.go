@@ -0,0 +1,156 @@
+package instago
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//The largest batch of updates RealtimeHandler will read
+const maxRealtimeBody = 1 << 20
+
+//Represents one notification sent by Instagram for a realtime subscription. MediaID is
+//only set for user subscriptions, where Instagram says which media was posted
+type RealtimeUpdate struct {
+	Object         SubscriptionObject
+	ObjectID       string
+	ChangedAspect  string
+	Time           time.Time
+	SubscriptionID string
+	MediaID        string
+}
+
+//Takes a realtime update JSON object and returns a RealtimeUpdate
+func RealtimeUpdateFromAPI(data JSON) RealtimeUpdate {
+	update := RealtimeUpdate{}
+	update.Object = SubscriptionObject(data.String("object"))
+	update.ObjectID = data.IDString("object_id")
+	update.ChangedAspect = data.String("changed_aspect")
+	update.Time = time.Unix(int64(data.Float("time")), 0)
+	update.SubscriptionID = data.IDString("subscription_id")
+	update.MediaID = data.Object("data").IDString("media_id")
+	return update
+}
+
+//RealtimeHandler is the http.Handler to serve at the callback URL of your realtime
+//subscriptions. It answers the hub.challenge Instagram sends when a subscription is
+//created (checking hub.verify_token against VerifyToken if it is set), verifies the
+//X-Hub-Signature of every batch of updates with ClientSecret and passes each update to
+//the callbacks registered for its kind of object. Batches that aren't signed correctly
+//are turned down with 403 Forbidden.
+//
+//Callbacks are called one after the other before Instagram gets its response, so they
+//should return quickly and hand any slow work (such as fetching media) off to another
+//goroutine
+type RealtimeHandler struct {
+	ClientSecret string
+	VerifyToken  string
+
+	mu        sync.RWMutex
+	callbacks map[SubscriptionObject][]func(RealtimeUpdate)
+}
+
+//Creates a RealtimeHandler for the given client secret and verify token
+func NewRealtimeHandler(clientSecret, verifyToken string) *RealtimeHandler {
+	return &RealtimeHandler{ClientSecret: clientSecret, VerifyToken: verifyToken}
+}
+
+//Registers a callback for updates about a kind of object. Several callbacks can be
+//registered for the same object and they are called in the order they were added
+//
+//object: The kind of object, such as SubscriptionTag
+//
+//callback: Called with every update about that kind of object
+func (handler *RealtimeHandler) Handle(object SubscriptionObject, callback func(RealtimeUpdate)) {
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	if handler.callbacks == nil {
+		handler.callbacks = make(map[SubscriptionObject][]func(RealtimeUpdate))
+	}
+	handler.callbacks[object] = append(handler.callbacks[object], callback)
+}
+
+//Implements http.Handler, answering GET handshakes and POSTed updates
+func (handler *RealtimeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		handler.serveChallenge(w, r)
+	case http.MethodPost:
+		handler.serveUpdates(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+//Answers the handshake Instagram makes when a subscription is created
+func (handler *RealtimeHandler) serveChallenge(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("hub.mode") != "subscribe" {
+		http.Error(w, "unknown hub.mode", http.StatusBadRequest)
+		return
+	}
+	if handler.VerifyToken != "" && query.Get("hub.verify_token") != handler.VerifyToken {
+		http.Error(w, "invalid hub.verify_token", http.StatusForbidden)
+		return
+	}
+	io.WriteString(w, query.Get("hub.challenge"))
+}
+
+//Checks and dispatches a batch of updates
+func (handler *RealtimeHandler) serveUpdates(w http.ResponseWriter, r *http.Request) {
+	//Read one byte past the limit to tell a batch that is too big from one that fits exactly
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxRealtimeBody+1))
+	if err != nil {
+		http.Error(w, "could not read body", http.StatusBadRequest)
+		return
+	}
+	if len(body) > maxRealtimeBody {
+		http.Error(w, "body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !VerifyRealtimeSignature(body, r.Header.Get("X-Hub-Signature"), handler.ClientSecret) {
+		http.Error(w, "invalid X-Hub-Signature", http.StatusForbidden)
+		return
+	}
+	var updates []interface{}
+	if err := json.Unmarshal(body, &updates); err != nil {
+		http.Error(w, "malformed updates", http.StatusBadRequest)
+		return
+	}
+
+	for _, data := range updates {
+		update := RealtimeUpdateFromAPI(JSONObject(data))
+		handler.mu.RLock()
+		callbacks := handler.callbacks[update.Object]
+		handler.mu.RUnlock()
+		for _, callback := range callbacks {
+			callback(update)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+//Computes the X-Hub-Signature Instagram sends with a batch of realtime updates: the hex
+//encoded HMAC-SHA1 of the body keyed with the client secret
+func RealtimeSignature(body []byte, clientSecret string) string {
+	mac := hmac.New(sha1.New, []byte(clientSecret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//Reports whether signature is the right X-Hub-Signature for body. An empty client secret
+//never verifies
+func VerifyRealtimeSignature(body []byte, signature, clientSecret string) bool {
+	if clientSecret == "" || signature == "" {
+		return false
+	}
+	expected := RealtimeSignature(body, clientSecret)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
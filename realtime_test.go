@@ -0,0 +1,127 @@
+package instago
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const realtimeBatch = `[
+	{"subscription_id": "1", "object": "tag", "object_id": "nofilter", "changed_aspect": "media", "time": 1297286541},
+	{"subscription_id": "2", "object": "location", "object_id": "1257285", "changed_aspect": "media", "time": 1297286541},
+	{"subscription_id": "3", "object": "geography", "object_id": 12345, "changed_aspect": "media", "time": 1297286541},
+	{"subscription_id": 4, "object": "user", "object_id": "1234", "changed_aspect": "media", "time": 1297286541, "data": {"media_id": "908540701891980503_1234"}}
+]`
+
+func newRealtimeServer(t *testing.T) (*httptest.Server, map[SubscriptionObject][]RealtimeUpdate) {
+	handler := NewRealtimeHandler("secret", "verify")
+	received := make(map[SubscriptionObject][]RealtimeUpdate)
+	for _, object := range []SubscriptionObject{SubscriptionTag, SubscriptionLocation, SubscriptionGeography, SubscriptionUser} {
+		handler.Handle(object, func(update RealtimeUpdate) {
+			received[update.Object] = append(received[update.Object], update)
+		})
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func postUpdates(t *testing.T, url, body, signature string) int {
+	req, err := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signature != "" {
+		req.Header.Set("X-Hub-Signature", signature)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRealtimeHandlerChallenge(t *testing.T) {
+	server, _ := newRealtimeServer(t)
+
+	resp, err := http.Get(server.URL + "?hub.mode=subscribe&hub.challenge=15f7d1a91c1f40f8a748fd134752feb3&hub.verify_token=verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "15f7d1a91c1f40f8a748fd134752feb3" {
+		t.Errorf("challenge answered with %d %q", resp.StatusCode, body)
+	}
+
+	resp, err = http.Get(server.URL + "?hub.mode=subscribe&hub.challenge=abc&hub.verify_token=wrong")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("wrong hub.verify_token answered with %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestRealtimeHandlerSignature(t *testing.T) {
+	server, received := newRealtimeServer(t)
+
+	for _, signature := range []string{"", "0123456789abcdef", RealtimeSignature([]byte(realtimeBatch), "other secret")} {
+		if status := postUpdates(t, server.URL, realtimeBatch, signature); status != http.StatusForbidden {
+			t.Errorf("signature %q answered with %d, want 403", signature, status)
+		}
+	}
+	if len(received) != 0 {
+		t.Errorf("unsigned updates reached the callbacks: %v", received)
+	}
+}
+
+func TestRealtimeHandlerDispatch(t *testing.T) {
+	server, received := newRealtimeServer(t)
+
+	if status := postUpdates(t, server.URL, realtimeBatch, RealtimeSignature([]byte(realtimeBatch), "secret")); status != http.StatusOK {
+		t.Fatalf("signed batch answered with %d, want 200", status)
+	}
+	want := map[SubscriptionObject]string{
+		SubscriptionTag:       "nofilter",
+		SubscriptionLocation:  "1257285",
+		SubscriptionGeography: "12345",
+		SubscriptionUser:      "1234",
+	}
+	for object, objectID := range want {
+		updates := received[object]
+		if len(updates) != 1 || updates[0].ObjectID != objectID || updates[0].ChangedAspect != AspectMedia {
+			t.Errorf("%s callback got %+v, want one update for %q", object, updates, objectID)
+		}
+	}
+	if mediaID := received[SubscriptionUser][0].MediaID; mediaID != "908540701891980503_1234" {
+		t.Errorf("user update MediaID = %q", mediaID)
+	}
+}
+
+func TestRealtimeHandlerTooLarge(t *testing.T) {
+	server, _ := newRealtimeServer(t)
+
+	body := "[" + strings.Repeat(" ", maxRealtimeBody) + "]"
+	if status := postUpdates(t, server.URL, body, RealtimeSignature([]byte(body), "secret")); status != http.StatusRequestEntityTooLarge {
+		t.Errorf("oversized batch answered with %d, want 413", status)
+	}
+}
+
+func TestRealtimeHandlerMethod(t *testing.T) {
+	server, _ := newRealtimeServer(t)
+
+	req, _ := http.NewRequest(http.MethodPut, server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") != "GET, POST" {
+		t.Errorf("PUT answered with %d (Allow: %q), want 405", resp.StatusCode, resp.Header.Get("Allow"))
+	}
+}
@@ -1,14 +1,59 @@
 package instago
 
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+//The authenticated user's side of a relationship
+type OutgoingStatus string
+
+const (
+	OutgoingFollows   OutgoingStatus = "follows"
+	OutgoingRequested OutgoingStatus = "requested"
+	OutgoingNone      OutgoingStatus = "none"
+)
+
+//The other user's side of a relationship
+type IncomingStatus string
+
+const (
+	IncomingFollowedBy   IncomingStatus = "followed_by"
+	IncomingRequestedBy  IncomingStatus = "requested_by"
+	IncomingBlockedByYou IncomingStatus = "blocked_by_you"
+	IncomingNone         IncomingStatus = "none"
+)
+
+//The changes ModifyRelationship can make to a relationship
+type RelationshipAction string
+
+const (
+	ActionFollow   RelationshipAction = "follow"
+	ActionUnfollow RelationshipAction = "unfollow"
+	ActionApprove  RelationshipAction = "approve"
+	ActionIgnore   RelationshipAction = "ignore"
+	ActionBlock    RelationshipAction = "block"
+	ActionUnblock  RelationshipAction = "unblock"
+)
+
 //Get the list of users this user is followed by.
 //
 //userID: a string representing the ID (not the username) of a given user
-func (api InstagramAPI) UserFollows(userID, cursor string) ([]User, Pagination, error) {
+func (api *InstagramAPI) UserFollows(userID, cursor string) ([]User, Pagination, error) {
+	return api.UserFollowsContext(context.Background(), userID, cursor)
+}
+
+//Same as UserFollows, but the request is bound to ctx
+func (api *InstagramAPI) UserFollowsContext(ctx context.Context, userID, cursor string) ([]User, Pagination, error) {
 	params := getEmptyMap()
 	if cursor != "" {
 		params["cursor"] = cursor
 	}
-	result := api.DoRequest("users/"+userID+"/follows", params)
+	result, err := api.DoRequestContext(ctx, "users/"+userID+"/follows", params)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
 	data := result.ObjectArray("data")
 	users := make([]User, 0)
 
@@ -17,18 +62,26 @@ func (api InstagramAPI) UserFollows(userID, cursor string) ([]User, Pagination,
 		users = append(users, u)
 	}
 	pagination := PaginationFromAPI(result.Object("pagination"))
-	return users, pagination, api.ErrorFromAPI(result)
+	return users, pagination, nil
 }
 
 //Get the list of
 //
 //userID: a string representing the ID (not the username) of a given user
-func (api InstagramAPI) UserFollowers(userID, cursor string) ([]User, Pagination, error) {
+func (api *InstagramAPI) UserFollowers(userID, cursor string) ([]User, Pagination, error) {
+	return api.UserFollowersContext(context.Background(), userID, cursor)
+}
+
+//Same as UserFollowers, but the request is bound to ctx
+func (api *InstagramAPI) UserFollowersContext(ctx context.Context, userID, cursor string) ([]User, Pagination, error) {
 	params := getEmptyMap()
 	if cursor != "" {
 		params["cursor"] = cursor
 	}
-	result := api.DoRequest("users/"+userID+"/followed-by", params)
+	result, err := api.DoRequestContext(ctx, "users/"+userID+"/followed-by", params)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
 	data := result.ObjectArray("data")
 	users := make([]User, 0)
 
@@ -37,5 +90,71 @@ func (api InstagramAPI) UserFollowers(userID, cursor string) ([]User, Pagination
 		users = append(users, u)
 	}
 	pagination := PaginationFromAPI(result.Object("pagination"))
-	return users, pagination, api.ErrorFromAPI(result)
+	return users, pagination, nil
+}
+
+//Gets how the authenticated user and another user are connected (requires OAuth with the
+//follower_list scope)
+//
+//userID: a string representing the ID (not the username) of a given user
+func (api *InstagramAPI) Relationship(userID string) (Relationship, error) {
+	return api.RelationshipContext(context.Background(), userID)
+}
+
+//Same as Relationship, but the request is bound to ctx
+func (api *InstagramAPI) RelationshipContext(ctx context.Context, userID string) (Relationship, error) {
+	params := getEmptyMap()
+	result, err := api.DoRequestContext(ctx, "users/"+userID+"/relationship", params)
+	if err != nil {
+		return Relationship{}, err
+	}
+	return RelationshipFromAPI(result.Object("data")), nil
+}
+
+//Get the list of users who have asked to follow the authenticated user (requires OAuth
+//with the follower_list scope)
+func (api *InstagramAPI) RequestedBy() ([]User, Pagination, error) {
+	return api.RequestedByContext(context.Background())
+}
+
+//Same as RequestedBy, but the request is bound to ctx
+func (api *InstagramAPI) RequestedByContext(ctx context.Context) ([]User, Pagination, error) {
+	params := getEmptyMap()
+	result, err := api.DoRequestContext(ctx, "users/self/requested-by", params)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
+	users := make([]User, 0)
+	for _, user := range result.ObjectArray("data") {
+		users = append(users, UserFromAPI(user))
+	}
+	pagination := PaginationFromAPI(result.Object("pagination"))
+	return users, pagination, nil
+}
+
+//Follows, unfollows, approves, ignores, blocks or unblocks a user as the authenticated
+//user (requires OAuth with the relationships scope) and returns the resulting
+//relationship
+//
+//userID: a string representing the ID (not the username) of a given user
+//
+//action: what to do, such as ActionFollow
+func (api *InstagramAPI) ModifyRelationship(userID string, action RelationshipAction) (Relationship, error) {
+	return api.ModifyRelationshipContext(context.Background(), userID, action)
+}
+
+//Same as ModifyRelationship, but the request is bound to ctx
+func (api *InstagramAPI) ModifyRelationshipContext(ctx context.Context, userID string, action RelationshipAction) (Relationship, error) {
+	switch action {
+	case ActionFollow, ActionUnfollow, ActionApprove, ActionIgnore, ActionBlock, ActionUnblock:
+	default:
+		return Relationship{}, fmt.Errorf("instago: unknown relationship action %q", action)
+	}
+	params := getEmptyMap()
+	params["action"] = string(action)
+	result, err := api.DoContext(ctx, http.MethodPost, "users/"+userID+"/relationship", params)
+	if err != nil {
+		return Relationship{}, err
+	}
+	return RelationshipFromAPI(result.Object("data")), nil
 }
@@ -0,0 +1,156 @@
+package instago
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//A RetryPolicy decides which failed GET requests are worth trying again and how long to
+//wait between attempts. Delays grow exponentially from BaseDelay up to MaxDelay and are
+//randomly shortened by up to Jitter (a fraction between 0 and 1) so that many clients
+//failing at once don't all retry at the same moment. If Instagram sends a Retry-After
+//header it is honoured, unless it asks for longer than MaxDelay in which case the
+//error is returned straight away.
+//
+//Network failures are always retried (unless the context has been cancelled), other
+//failures only if their HTTP status is listed in RetryableStatusCodes or their
+//error_type in RetryableErrorTypes. Set OnRetry to be told about each retry before it
+//happens
+type RetryPolicy struct {
+	MaxAttempts          int
+	BaseDelay            time.Duration
+	MaxDelay             time.Duration
+	Jitter               float64
+	RetryableStatusCodes []int
+	RetryableErrorTypes  []string
+	OnRetry              func(RetryEvent)
+}
+
+//Describes a failed attempt that is about to be retried
+type RetryEvent struct {
+	Endpoint string
+	Attempt  int
+	Err      error
+	Delay    time.Duration
+}
+
+//Returns a policy that makes up to 4 attempts, starting at half a second between them,
+//for network failures and 5xx responses
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   500 * time.Millisecond,
+		MaxDelay:    30 * time.Second,
+		Jitter:      0.5,
+		RetryableStatusCodes: []int{
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+//Works out whether the given failed attempt should be retried and, if so, how long to
+//wait first. A nil policy never retries
+func (policy *RetryPolicy) shouldRetry(ctx context.Context, attempt int, err error) (time.Duration, bool) {
+	if policy == nil || attempt >= policy.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+	if !policy.retryable(err) {
+		return 0, false
+	}
+
+	delay := policy.backoff(attempt)
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.RetryAfter > delay {
+		if policy.MaxDelay > 0 && apiErr.RetryAfter > policy.MaxDelay {
+			return 0, false
+		}
+		delay = apiErr.RetryAfter
+	}
+	return delay, true
+}
+
+//Reports whether the policy considers err to be transient
+func (policy *RetryPolicy) retryable(err error) bool {
+	if errors.Is(err, ErrNetwork) {
+		return true
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	for _, code := range policy.RetryableStatusCodes {
+		if apiErr.StatusCode == code {
+			return true
+		}
+	}
+	for _, errorType := range policy.RetryableErrorTypes {
+		if apiErr.Type == errorType {
+			return true
+		}
+	}
+	return false
+}
+
+//The exponential delay (with jitter) before retrying after the given attempt
+func (policy *RetryPolicy) backoff(attempt int) time.Duration {
+	delay := policy.BaseDelay
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if policy.MaxDelay > 0 && delay >= policy.MaxDelay {
+			break
+		}
+	}
+	if policy.MaxDelay > 0 && delay > policy.MaxDelay {
+		delay = policy.MaxDelay
+	}
+	if policy.Jitter > 0 {
+		delay -= time.Duration(rand.Float64() * policy.Jitter * float64(delay))
+	}
+	return delay
+}
+
+//Calls OnRetry if it has been set
+func (policy *RetryPolicy) notify(event RetryEvent) {
+	if policy != nil && policy.OnRetry != nil {
+		policy.OnRetry(event)
+	}
+}
+
+//Reads a Retry-After header given either in seconds or as an HTTP date
+func retryAfterFromHeader(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		if wait := time.Until(t); wait > 0 {
+			return wait
+		}
+	}
+	return 0
+}
+
+//Waits for the given duration, giving up early if ctx is done
+func sleepContext(ctx context.Context, delay time.Duration) error {
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
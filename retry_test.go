@@ -0,0 +1,143 @@
+package instago
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Answers with the given status codes in turn, then with a successful response
+func newFlakyServer(t *testing.T, statuses ...int) (*httptest.Server, func() int) {
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		n := requests
+		requests++
+		mu.Unlock()
+		if n < len(statuses) {
+			w.WriteHeader(statuses[n])
+			return
+		}
+		w.Write([]byte(`{"meta": {"code": 200}, "data": {}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests
+	}
+}
+
+func testRetryPolicy() *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	return policy
+}
+
+func TestRetryTransientFailures(t *testing.T) {
+	server, requests := newFlakyServer(t, http.StatusServiceUnavailable, http.StatusBadGateway)
+	policy := testRetryPolicy()
+	var events []RetryEvent
+	policy.OnRetry = func(event RetryEvent) { events = append(events, event) }
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/", Retry: policy}
+
+	if _, err := api.DoRequest("users/self", nil); err != nil {
+		t.Fatalf("DoRequest: %v", err)
+	}
+	if requests() != 3 {
+		t.Errorf("%d requests, want 3", requests())
+	}
+	if len(events) != 2 || events[0].Attempt != 1 || events[1].Attempt != 2 || events[0].Endpoint != "users/self" {
+		t.Errorf("OnRetry got %+v", events)
+	}
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	server, requests := newFlakyServer(t, 500, 500, 500, 500, 500)
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/", Retry: testRetryPolicy()}
+
+	_, err := api.DoRequest("users/self", nil)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 500 {
+		t.Errorf("DoRequest returned %v, want the last 500", err)
+	}
+	if requests() != 4 {
+		t.Errorf("%d requests, want MaxAttempts (4)", requests())
+	}
+}
+
+func TestRetryOnlyGET(t *testing.T) {
+	server, requests := newFlakyServer(t, http.StatusServiceUnavailable)
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/", Retry: testRetryPolicy()}
+
+	if _, err := api.Do(http.MethodPost, "media/1/likes", nil); err == nil {
+		t.Error("POST succeeded, want the 503")
+	}
+	if requests() != 1 {
+		t.Errorf("%d requests, want the POST sent once", requests())
+	}
+}
+
+func TestRetryNotRetryable(t *testing.T) {
+	server, requests := newFlakyServer(t, http.StatusNotFound)
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/", Retry: testRetryPolicy()}
+
+	if _, err := api.DoRequest("users/self", nil); err == nil {
+		t.Error("DoRequest succeeded, want the 404")
+	}
+	if requests() != 1 {
+		t.Errorf("%d requests, want 1", requests())
+	}
+}
+
+func TestRetryAfterTooLong(t *testing.T) {
+	policy := testRetryPolicy()
+	err := &APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Minute}
+	if _, retry := policy.shouldRetry(context.Background(), 1, err); retry {
+		t.Error("retried although Retry-After asked for longer than MaxDelay")
+	}
+	err.RetryAfter = 5 * time.Millisecond
+	if delay, retry := policy.shouldRetry(context.Background(), 1, err); !retry || delay != 5*time.Millisecond {
+		t.Errorf("shouldRetry = %v, %v, want the Retry-After of 5ms", delay, retry)
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := &RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 40 * time.Millisecond}
+	want := []time.Duration{10, 20, 40, 40, 40}
+	for i, delay := range want {
+		if got := policy.backoff(i + 1); got != delay*time.Millisecond {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, delay*time.Millisecond)
+		}
+	}
+
+	policy.Jitter = 0.5
+	for attempt := 1; attempt <= 5; attempt++ {
+		full := want[attempt-1] * time.Millisecond
+		if got := policy.backoff(attempt); got > full || got < full/2 {
+			t.Errorf("backoff(%d) with jitter = %v, want between %v and %v", attempt, got, full/2, full)
+		}
+	}
+}
+
+func TestRetryContextCancelled(t *testing.T) {
+	server, _ := newFlakyServer(t, 500, 500, 500, 500)
+	policy := testRetryPolicy()
+	policy.BaseDelay = time.Second
+	policy.MaxDelay = time.Second
+	policy.Jitter = 0
+	api := &InstagramAPI{AccessToken: "token", BaseURL: server.URL + "/", Retry: policy}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := api.DoRequestContext(ctx, "users/self", nil)
+	if !errors.Is(err, ErrNetwork) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("DoRequestContext returned %v, want ErrNetwork wrapping the deadline", err)
+	}
+}
@@ -0,0 +1,72 @@
+package instago
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+//The alphabet Instagram encodes shortcodes with: URL-safe base64, most significant digit
+//first
+const shortcodeAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+
+//Works out the shortcode used in a post's link from its media ID, without asking
+//Instagram. Media IDs look like 1234567890123456789_987654 where the part after the
+//underscore is the ID of the user; only the part before it is encoded
+//
+//id: The ID of the media, with or without the _userID suffix
+func ShortcodeFromID(id string) (string, error) {
+	numeric := strings.SplitN(id, "_", 2)[0]
+	//big.Int would also accept a sign or underscores, which media IDs never have
+	if numeric == "" || strings.Trim(numeric, "0123456789") != "" {
+		return "", fmt.Errorf("instago: %q is not a media ID", id)
+	}
+	n, _ := new(big.Int).SetString(numeric, 10)
+	if n.Sign() == 0 {
+		return shortcodeAlphabet[:1], nil
+	}
+
+	base := big.NewInt(int64(len(shortcodeAlphabet)))
+	digit := new(big.Int)
+	code := make([]byte, 0, 11)
+	for n.Sign() > 0 {
+		n.DivMod(n, base, digit)
+		code = append(code, shortcodeAlphabet[digit.Int64()])
+	}
+	for i, j := 0, len(code)-1; i < j; i, j = i+1, j-1 {
+		code[i], code[j] = code[j], code[i]
+	}
+	return string(code), nil
+}
+
+//Works out the numeric part of a media ID from the shortcode used in a post's link,
+//without asking Instagram. The result doesn't include the _userID suffix, which can't be
+//recovered from the shortcode
+//
+//code: The shortcode, such as AbC123 in https://instagram.com/p/AbC123/
+func IDFromShortcode(code string) (string, error) {
+	if code == "" {
+		return "", fmt.Errorf("instago: empty shortcode")
+	}
+	base := big.NewInt(int64(len(shortcodeAlphabet)))
+	n := new(big.Int)
+	for _, r := range code {
+		digit := strings.IndexRune(shortcodeAlphabet, r)
+		if digit < 0 {
+			return "", fmt.Errorf("instago: %q is not a valid shortcode", code)
+		}
+		n.Mul(n, base)
+		n.Add(n, big.NewInt(int64(digit)))
+	}
+	return n.String(), nil
+}
+
+//Returns the shortcode used in the link to this media, or "" if the ID is not a valid
+//media ID
+func (media Media) Shortcode() string {
+	code, err := ShortcodeFromID(media.ID)
+	if err != nil {
+		return ""
+	}
+	return code
+}
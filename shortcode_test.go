@@ -0,0 +1,68 @@
+package instago
+
+import "testing"
+
+var shortcodePairs = []struct {
+	id   string
+	code string
+}{
+	{"2155832952940083788", "B3rDqYLEzpM"},
+	{"908540701891980503", "ybyPRoQWzX"},
+	{"0", "A"},
+	{"1", "B"},
+	{"63", "_"},
+	{"64", "BA"},
+}
+
+func TestShortcodeFromID(t *testing.T) {
+	for _, pair := range shortcodePairs {
+		for _, id := range []string{pair.id, pair.id + "_25025320"} {
+			code, err := ShortcodeFromID(id)
+			if err != nil {
+				t.Errorf("ShortcodeFromID(%q) returned error: %v", id, err)
+				continue
+			}
+			if code != pair.code {
+				t.Errorf("ShortcodeFromID(%q) = %q, want %q", id, code, pair.code)
+			}
+		}
+	}
+}
+
+func TestIDFromShortcode(t *testing.T) {
+	for _, pair := range shortcodePairs {
+		id, err := IDFromShortcode(pair.code)
+		if err != nil {
+			t.Errorf("IDFromShortcode(%q) returned error: %v", pair.code, err)
+			continue
+		}
+		if id != pair.id {
+			t.Errorf("IDFromShortcode(%q) = %q, want %q", pair.code, id, pair.id)
+		}
+	}
+}
+
+func TestMediaShortcode(t *testing.T) {
+	for _, pair := range shortcodePairs {
+		media := Media{ID: pair.id + "_25025320"}
+		if code := media.Shortcode(); code != pair.code {
+			t.Errorf("Media{ID: %q}.Shortcode() = %q, want %q", media.ID, code, pair.code)
+		}
+	}
+	if code := (Media{ID: "not-an-id"}).Shortcode(); code != "" {
+		t.Errorf("Shortcode() of an invalid ID = %q, want \"\"", code)
+	}
+}
+
+func TestShortcodeInvalid(t *testing.T) {
+	for _, id := range []string{"", "_25025320", "-5", "+5", "12a4", " 12"} {
+		if code, err := ShortcodeFromID(id); err == nil {
+			t.Errorf("ShortcodeFromID(%q) = %q, want an error", id, code)
+		}
+	}
+	for _, code := range []string{"", "AbC!23", "a b", "ü"} {
+		if id, err := IDFromShortcode(code); err == nil {
+			t.Errorf("IDFromShortcode(%q) = %q, want an error", code, id)
+		}
+	}
+}
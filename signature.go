@@ -0,0 +1,38 @@
+package instago
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+)
+
+//Computes the sig parameter Instagram requires from clients with "Enforce signed
+//requests" enabled: an HMAC-SHA256, keyed with the client secret, of the endpoint path
+//followed by every parameter (sorted by name) as |name=value
+//
+//endpoint: The API request being signed, relative to the base URL, such as media/123
+//
+//params: Every parameter sent with the request, including access_token or client_id.
+//A sig parameter, if present, is ignored
+//
+//secret: The client secret
+func GenerateSignature(endpoint string, params map[string]string, secret string) string {
+	endpoint = strings.SplitN(endpoint, "?", 2)[0]
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if key != "sig" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	message := "/" + strings.TrimPrefix(endpoint, "/")
+	for _, key := range keys {
+		message += "|" + key + "=" + params[key]
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
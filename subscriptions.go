@@ -0,0 +1,215 @@
+package instago
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+//The kinds of object a realtime subscription can follow
+type SubscriptionObject string
+
+const (
+	SubscriptionTag       SubscriptionObject = "tag"
+	SubscriptionUser      SubscriptionObject = "user"
+	SubscriptionLocation  SubscriptionObject = "location"
+	SubscriptionGeography SubscriptionObject = "geography"
+)
+
+//The only aspect Instagram supports: new media being posted
+const AspectMedia = "media"
+
+//Returned by the subscription methods when the ClientID or ClientSecret is missing
+var ErrNoClientSecret = errors.New("instago: subscriptions require a ClientID and ClientSecret")
+
+//Represents a realtime subscription. For geography subscriptions ObjectID is the ID of
+//the geography Instagram created, to be used with GeographyRecentMedia
+type Subscription struct {
+	ID          string
+	Object      SubscriptionObject
+	ObjectID    string
+	Aspect      string
+	CallbackURL string
+}
+
+//Takes a subscription API JSON response and returns a Subscription
+func SubscriptionFromAPI(data JSON) Subscription {
+	subscription := Subscription{}
+	subscription.ID = data.IDString("id")
+	subscription.Object = SubscriptionObject(data.String("object"))
+	subscription.ObjectID = data.IDString("object_id")
+	subscription.Aspect = data.String("aspect")
+	subscription.CallbackURL = data.String("callback_url")
+	return subscription
+}
+
+//Asks Instagram to POST to callbackURL whenever aspect (AspectMedia) changes for the
+//given object. Instagram first checks the callback by sending it verifyToken, see
+//RealtimeHandler. Requires ClientID and ClientSecret
+//
+//object: SubscriptionTag, SubscriptionUser or SubscriptionLocation. Use
+//CreateGeographySubscription for geographies
+//
+//aspect: What to be told about, AspectMedia
+//
+//objectID: The tag name or location ID. User subscriptions cover every user who has
+//authorized your application, so objectID is ignored for them
+//
+//callbackURL: Where Instagram should send updates
+//
+//verifyToken: (optional = "") A value Instagram sends back to the callback to prove the
+//subscription was requested by you
+func (api *InstagramAPI) CreateSubscription(object SubscriptionObject, aspect, objectID, callbackURL, verifyToken string) (Subscription, error) {
+	return api.CreateSubscriptionContext(context.Background(), object, aspect, objectID, callbackURL, verifyToken)
+}
+
+//Same as CreateSubscription, but the request is bound to ctx
+func (api *InstagramAPI) CreateSubscriptionContext(ctx context.Context, object SubscriptionObject, aspect, objectID, callbackURL, verifyToken string) (Subscription, error) {
+	params := getEmptyMap()
+	switch object {
+	case SubscriptionTag, SubscriptionLocation:
+		params["object_id"] = objectID
+	case SubscriptionUser:
+	case SubscriptionGeography:
+		return Subscription{}, errors.New("instago: use CreateGeographySubscription to subscribe to a geography")
+	default:
+		return Subscription{}, fmt.Errorf("instago: unknown subscription object %q", object)
+	}
+	params["object"] = string(object)
+	return api.createSubscription(ctx, params, aspect, callbackURL, verifyToken)
+}
+
+//Asks Instagram to POST to callbackURL whenever media is posted within radius meters of
+//a point. Instagram creates a geography for the area; its ID is the ObjectID of the
+//returned Subscription. Requires ClientID and ClientSecret
+//
+//lat: The latitude of the center of the area
+//
+//lng: The longitude of the center of the area
+//
+//radius: The radius of the area in meters (up to 5000)
+//
+//callbackURL: Where Instagram should send updates
+//
+//verifyToken: (optional = "") A value Instagram sends back to the callback to prove the
+//subscription was requested by you
+func (api *InstagramAPI) CreateGeographySubscription(lat, lng, radius float64, callbackURL, verifyToken string) (Subscription, error) {
+	return api.CreateGeographySubscriptionContext(context.Background(), lat, lng, radius, callbackURL, verifyToken)
+}
+
+//Same as CreateGeographySubscription, but the request is bound to ctx
+func (api *InstagramAPI) CreateGeographySubscriptionContext(ctx context.Context, lat, lng, radius float64, callbackURL, verifyToken string) (Subscription, error) {
+	params := getEmptyMap()
+	params["object"] = string(SubscriptionGeography)
+	params["lat"] = fmt.Sprintf("%f", lat)
+	params["lng"] = fmt.Sprintf("%f", lng)
+	params["radius"] = fmt.Sprintf("%f", radius)
+	return api.createSubscription(ctx, params, AspectMedia, callbackURL, verifyToken)
+}
+
+//Both CreateSubscription and CreateGeographySubscription post to subscriptions
+func (api *InstagramAPI) createSubscription(ctx context.Context, params map[string]string, aspect, callbackURL, verifyToken string) (Subscription, error) {
+	if api.ClientID == "" || api.ClientSecret == "" {
+		return Subscription{}, ErrNoClientSecret
+	}
+	params["aspect"] = aspect
+	params["callback_url"] = callbackURL
+	if verifyToken != "" {
+		params["verify_token"] = verifyToken
+	}
+	result, err := api.do(ctx, http.MethodPost, "subscriptions", params, appCredentials)
+	if err != nil {
+		return Subscription{}, err
+	}
+	return SubscriptionFromAPI(result.Object("data")), nil
+}
+
+//Gets every realtime subscription of your application. Requires ClientID and
+//ClientSecret
+func (api *InstagramAPI) ListSubscriptions() ([]Subscription, error) {
+	return api.ListSubscriptionsContext(context.Background())
+}
+
+//Same as ListSubscriptions, but the request is bound to ctx
+func (api *InstagramAPI) ListSubscriptionsContext(ctx context.Context) ([]Subscription, error) {
+	if api.ClientID == "" || api.ClientSecret == "" {
+		return nil, ErrNoClientSecret
+	}
+	params := getEmptyMap()
+	result, err := api.do(ctx, http.MethodGet, "subscriptions", params, appCredentials)
+	if err != nil {
+		return nil, err
+	}
+	subscriptions := make([]Subscription, 0)
+	for _, subscription := range result.ObjectArray("data") {
+		subscriptions = append(subscriptions, SubscriptionFromAPI(subscription))
+	}
+	return subscriptions, nil
+}
+
+//Removes a single realtime subscription. Requires ClientID and ClientSecret
+//
+//id: The ID of the subscription
+func (api *InstagramAPI) DeleteSubscription(id string) error {
+	return api.DeleteSubscriptionContext(context.Background(), id)
+}
+
+//Same as DeleteSubscription, but the request is bound to ctx
+func (api *InstagramAPI) DeleteSubscriptionContext(ctx context.Context, id string) error {
+	params := getEmptyMap()
+	params["id"] = id
+	return api.deleteSubscriptions(ctx, params)
+}
+
+//Removes every realtime subscription to a kind of object, such as all tag
+//subscriptions. Requires ClientID and ClientSecret
+//
+//object: The kind of object, such as SubscriptionTag
+func (api *InstagramAPI) DeleteSubscriptionsByObject(object SubscriptionObject) error {
+	return api.DeleteSubscriptionsByObjectContext(context.Background(), object)
+}
+
+//Same as DeleteSubscriptionsByObject, but the request is bound to ctx
+func (api *InstagramAPI) DeleteSubscriptionsByObjectContext(ctx context.Context, object SubscriptionObject) error {
+	params := getEmptyMap()
+	params["object"] = string(object)
+	return api.deleteSubscriptions(ctx, params)
+}
+
+//Removes every realtime subscription of your application. Requires ClientID and
+//ClientSecret
+func (api *InstagramAPI) DeleteAllSubscriptions() error {
+	return api.DeleteAllSubscriptionsContext(context.Background())
+}
+
+//Same as DeleteAllSubscriptions, but the request is bound to ctx
+func (api *InstagramAPI) DeleteAllSubscriptionsContext(ctx context.Context) error {
+	params := getEmptyMap()
+	params["object"] = "all"
+	return api.deleteSubscriptions(ctx, params)
+}
+
+//All the ways of deleting subscriptions send DELETE to subscriptions
+func (api *InstagramAPI) deleteSubscriptions(ctx context.Context, params map[string]string) error {
+	if api.ClientID == "" || api.ClientSecret == "" {
+		return ErrNoClientSecret
+	}
+	_, err := api.do(ctx, http.MethodDelete, "subscriptions", params, appCredentials)
+	return err
+}
+
+//Gets the media recently posted in a geography created by CreateGeographySubscription.
+//Only the ClientID is sent, as Instagram requires for this endpoint
+//
+//geoID: The ID of the geography (Subscription.ObjectID)
+//
+//query: Which media to return; only After (a media ID) and Count are used
+func (api *InstagramAPI) GeographyRecentMedia(geoID string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.GeographyRecentMediaContext(context.Background(), geoID, query)
+}
+
+//Same as GeographyRecentMedia, but the request is bound to ctx
+func (api *InstagramAPI) GeographyRecentMediaContext(ctx context.Context, geoID string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.mediaListWith(ctx, "geographies/"+geoID+"/media/recent", query.params(false), clientCredentials)
+}
@@ -1,8 +1,6 @@
 package instago
 
-import (
-	"fmt"
-)
+import "context"
 
 //Gets all (16) recent photos with the given tag
 //
@@ -11,57 +9,70 @@ import (
 //before: (optional - use "") find photos posted before this ID (use Media.ID)
 //
 //after: (optional - use "") find photos posted after this ID (use Media.ID)
-func (api InstagramAPI) TagRecent(tag, before, after string, max int) ([]Media, Pagination, error) {
-	// return api.GenericMediaListRequest("tags/"+tag+"/media/recent", before, after, 0)
-	// var max int
-	params := getEmptyMap()
-	if max > 0 {
-		params["count"] = fmt.Sprintf("%d", max)
-	}
-	if before != "" {
-		params["max_tag_id"] = before
-	}
-	if after != "" {
-		params["min_tag_id"] = after
-	}
+func (api *InstagramAPI) TagRecent(tag, before, after string, max int) ([]Media, Pagination, error) {
+	return api.TagRecentContext(context.Background(), tag, before, after, max)
+}
 
-	results := api.DoRequest("tags/"+tag+"/media/recent", params)
-	data := results.ObjectArray("data")
-	media_objects := make([]Media, 0)
-	for _, media := range data {
-		media_objects = append(media_objects, MediaFromAPI(media))
-	}
-	pagination := PaginationFromAPI(results.Object("pagination"))
-	err := api.ErrorFromAPI(results)
+//Same as TagRecent, but the request is bound to ctx
+func (api *InstagramAPI) TagRecentContext(ctx context.Context, tag, before, after string, max int) ([]Media, Pagination, error) {
+	return api.TagRecentQueryContext(ctx, tag, MediaQuery{Before: before, After: after, Count: max})
+}
+
+//Same as TagRecent, but takes a MediaQuery. Before and After are tag IDs (see
+//Pagination.NextMaxTagId and Pagination.MinTagId) rather than media IDs
+//
+//tag: The tag (don't include the # hash) that you want to fetch
+//
+//query: Which media to return
+func (api *InstagramAPI) TagRecentQuery(tag string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.TagRecentQueryContext(context.Background(), tag, query)
+}
 
-	return media_objects, pagination, err
+//Same as TagRecentQuery, but the request is bound to ctx
+func (api *InstagramAPI) TagRecentQueryContext(ctx context.Context, tag string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.mediaList(ctx, "tags/"+tag+"/media/recent", query.params(true))
 }
 
 //Gets the total number of media objects on Instagram with a given tag
 //
 //tag: a string that represents the tag that you want to search for
-func (api InstagramAPI) TagInfo(tag string) Tag {
+func (api *InstagramAPI) TagInfo(tag string) (Tag, error) {
+	return api.TagInfoContext(context.Background(), tag)
+}
+
+//Same as TagInfo, but the request is bound to ctx
+func (api *InstagramAPI) TagInfoContext(ctx context.Context, tag string) (Tag, error) {
 	params := getEmptyMap()
-	result := api.DoRequest("tags/"+tag, params)
-	return tagObject(result.Object("data"))
+	result, err := api.DoRequestContext(ctx, "tags/"+tag, params)
+	if err != nil {
+		return Tag{}, err
+	}
+	return tagObject(result.Object("data")), nil
 }
 
 //Will fetch the tag along with similar tags from Instagram so you can see the number of
 //media objects (posts) with that tag
 //
 //tag: a string that represents the tag you want to search for
-func (api InstagramAPI) TagSearch(tag string) ([]Tag, Pagination, error) {
+func (api *InstagramAPI) TagSearch(tag string) ([]Tag, Pagination, error) {
+	return api.TagSearchContext(context.Background(), tag)
+}
+
+//Same as TagSearch, but the request is bound to ctx
+func (api *InstagramAPI) TagSearchContext(ctx context.Context, tag string) ([]Tag, Pagination, error) {
 	params := getEmptyMap()
 	params["q"] = tag
-	result := api.DoRequest("tags/search", params)
+	result, err := api.DoRequestContext(ctx, "tags/search", params)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
 	tags := make([]Tag, 0)
 	for _, tag := range result.ObjectArray("data") {
 		tags = append(tags, tagObject(tag))
 	}
 	pagination := PaginationFromAPI(result.Object("pagination"))
-	err := api.ErrorFromAPI(result)
 
-	return tags, pagination, err
+	return tags, pagination, nil
 }
 
 //Both TagInfo and TagSearch need to create Tag objects
@@ -1,15 +1,64 @@
 package instago
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 //Gets basic information about a given user
 //
 //userID: a string representing the ID (not the username) of a given user
-func (api InstagramAPI) UserDetail(userID string) (User, error) {
+func (api *InstagramAPI) UserDetail(userID string) (User, error) {
+	return api.UserDetailContext(context.Background(), userID)
+}
+
+//Same as UserDetail, but the request is bound to ctx
+func (api *InstagramAPI) UserDetailContext(ctx context.Context, userID string) (User, error) {
 	params := getEmptyMap()
-	result := api.DoRequest("users/"+userID, params)
+	result, err := api.DoRequestContext(ctx, "users/"+userID, params)
+	if err != nil {
+		return User{}, err
+	}
 	data := result.Object("data")
-	return UserFromAPI(data), api.ErrorFromAPI(result)
+	return UserFromAPI(data), nil
+}
+
+//Gets the profile of the user the AccessToken belongs to (requires OAuth)
+func (api *InstagramAPI) Self() (User, error) {
+	return api.SelfContext(context.Background())
+}
+
+//Same as Self, but the request is bound to ctx
+func (api *InstagramAPI) SelfContext(ctx context.Context) (User, error) {
+	return api.UserDetailContext(ctx, "self")
+}
+
+//The result of checking an access token with TokenInfo
+type TokenInfo struct {
+	Valid bool
+	User  User
+}
+
+//Checks whether the AccessToken is still accepted by Instagram and, if it is, which user
+//it belongs to. A token that has been revoked or has expired is reported with Valid set
+//to false rather than as an error; an error is only returned if the check itself failed
+func (api *InstagramAPI) TokenInfo() (TokenInfo, error) {
+	return api.TokenInfoContext(context.Background())
+}
+
+//Same as TokenInfo, but the request is bound to ctx
+func (api *InstagramAPI) TokenInfoContext(ctx context.Context) (TokenInfo, error) {
+	if api.AccessToken == "" {
+		return TokenInfo{}, nil
+	}
+	user, err := api.SelfContext(ctx)
+	if IsAuthError(err) {
+		return TokenInfo{}, nil
+	}
+	if err != nil {
+		return TokenInfo{}, err
+	}
+	return TokenInfo{Valid: true, User: user}, nil
 }
 
 //Query the users on Instagram and get a list of them back
@@ -17,22 +66,29 @@ func (api InstagramAPI) UserDetail(userID string) (User, error) {
 //query: The description such as 'jack' or 'thomas' to search for
 //
 //max: (optional, default = 0) the number of users to return
-func (api InstagramAPI) SearchUsers(query string, max int) ([]User, Pagination, error) {
+func (api *InstagramAPI) SearchUsers(query string, max int) ([]User, Pagination, error) {
+	return api.SearchUsersContext(context.Background(), query, max)
+}
+
+//Same as SearchUsers, but the request is bound to ctx
+func (api *InstagramAPI) SearchUsersContext(ctx context.Context, query string, max int) ([]User, Pagination, error) {
 	params := getEmptyMap()
 	params["q"] = query
 	if max > 0 {
 		params["count"] = fmt.Sprintf("%d", max)
 	}
-	result := api.DoRequest("users/search", params)
+	result, err := api.DoRequestContext(ctx, "users/search", params)
+	if err != nil {
+		return nil, Pagination{}, err
+	}
 	data := result.ObjectArray("data")
 	users := make([]User, 0)
 	for _, user := range data {
 		users = append(users, UserFromAPI(user))
 	}
 	pagination := PaginationFromAPI(result.Object("pagination"))
-	err := api.ErrorFromAPI(result)
 
-	return users, pagination, err
+	return users, pagination, nil
 }
 
 //Will return an array of recently posted media objects by a user. Requires OAuth
@@ -44,8 +100,58 @@ func (api InstagramAPI) SearchUsers(query string, max int) ([]User, Pagination,
 //before: (optional = "") posts before a certain ID
 //
 //after: (optional = "") posts after a certain ID
-func (api InstagramAPI) RecentPostsByUser(userId string, max int, before, after string) ([]Media, Pagination, error) {
-	return api.GenericMediaListRequest("users/"+userId+"/media/recent", before, after, max)
+func (api *InstagramAPI) RecentPostsByUser(userId string, max int, before, after string) ([]Media, Pagination, error) {
+	return api.RecentPostsByUserContext(context.Background(), userId, max, before, after)
+}
+
+//Same as RecentPostsByUser, but the request is bound to ctx
+func (api *InstagramAPI) RecentPostsByUserContext(ctx context.Context, userId string, max int, before, after string) ([]Media, Pagination, error) {
+	return api.RecentPostsByUserQueryContext(ctx, userId, MediaQuery{Before: before, After: after, Count: max})
+}
+
+//Same as RecentPostsByUser, but takes a MediaQuery so that the media can also be narrowed
+//down by time
+//
+//userId: string representing the user
+//
+//query: Which media to return
+func (api *InstagramAPI) RecentPostsByUserQuery(userId string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.RecentPostsByUserQueryContext(context.Background(), userId, query)
+}
+
+//Same as RecentPostsByUserQuery, but the request is bound to ctx
+func (api *InstagramAPI) RecentPostsByUserQueryContext(ctx context.Context, userId string, query MediaQuery) ([]Media, Pagination, error) {
+	return api.GenericMediaListQueryContext(ctx, "users/"+userId+"/media/recent", query)
+}
+
+//Will return an array of media objects recently posted by the user the AccessToken
+//belongs to (requires OAuth)
+//
+//max: (optional = 0) the greatest number of media objects to return
+//
+//before: (optional = "") posts before a certain ID
+//
+//after: (optional = "") posts after a certain ID
+func (api *InstagramAPI) SelfRecentMedia(max int, before, after string) ([]Media, Pagination, error) {
+	return api.SelfRecentMediaContext(context.Background(), max, before, after)
+}
+
+//Same as SelfRecentMedia, but the request is bound to ctx
+func (api *InstagramAPI) SelfRecentMediaContext(ctx context.Context, max int, before, after string) ([]Media, Pagination, error) {
+	return api.SelfRecentMediaQueryContext(ctx, MediaQuery{Before: before, After: after, Count: max})
+}
+
+//Same as SelfRecentMedia, but takes a MediaQuery so that the media can also be narrowed
+//down by time
+//
+//query: Which media to return
+func (api *InstagramAPI) SelfRecentMediaQuery(query MediaQuery) ([]Media, Pagination, error) {
+	return api.SelfRecentMediaQueryContext(context.Background(), query)
+}
+
+//Same as SelfRecentMediaQuery, but the request is bound to ctx
+func (api *InstagramAPI) SelfRecentMediaQueryContext(ctx context.Context, query MediaQuery) ([]Media, Pagination, error) {
+	return api.GenericMediaListQueryContext(ctx, "users/self/media/recent", query)
 }
 
 //Gets the current user's feed (requires OAuth)
@@ -55,15 +161,54 @@ func (api InstagramAPI) RecentPostsByUser(userId string, max int, before, after
 //after: (optional = "") posts after a certain ID
 //
 //max: (optional = 0) the greatest number of media objects to return
-func (api InstagramAPI) Feed(before, after string, max int) ([]Media, Pagination, error) {
-	return api.GenericMediaListRequest("users/self/feed", before, after, max)
+func (api *InstagramAPI) Feed(before, after string, max int) ([]Media, Pagination, error) {
+	return api.FeedContext(context.Background(), before, after, max)
+}
+
+//Same as Feed, but the request is bound to ctx
+func (api *InstagramAPI) FeedContext(ctx context.Context, before, after string, max int) ([]Media, Pagination, error) {
+	return api.FeedQueryContext(ctx, MediaQuery{Before: before, After: after, Count: max})
+}
+
+//Same as Feed, but takes a MediaQuery
+//
+//query: Which media to return
+func (api *InstagramAPI) FeedQuery(query MediaQuery) ([]Media, Pagination, error) {
+	return api.FeedQueryContext(context.Background(), query)
+}
+
+//Same as FeedQuery, but the request is bound to ctx
+func (api *InstagramAPI) FeedQueryContext(ctx context.Context, query MediaQuery) ([]Media, Pagination, error) {
+	return api.GenericMediaListQueryContext(ctx, "users/self/feed", query)
 }
 
 //Gets the posts like by the current user (requires OAuth)
 //
 //max: (optional = 0) the greatest number of posts to return
 //
-//before: (optional = 0) posts liked before a certain media ID
-func (api InstagramAPI) Liked(max int, before string) ([]Media, Pagination, error) {
-	return api.GenericMediaListRequest("users/self/media/liked", before, "", max)
+//before: (optional = "") posts liked before this one (Pagination.NextMaxLikeId)
+func (api *InstagramAPI) Liked(max int, before string) ([]Media, Pagination, error) {
+	return api.LikedContext(context.Background(), max, before)
+}
+
+//Same as Liked, but the request is bound to ctx
+func (api *InstagramAPI) LikedContext(ctx context.Context, max int, before string) ([]Media, Pagination, error) {
+	return api.LikedQueryContext(ctx, MediaQuery{Before: before, Count: max})
+}
+
+//Same as Liked, but takes a MediaQuery. This endpoint pages by like rather than by media,
+//so Before is a like ID (see Pagination.NextMaxLikeId) and only Before and Count are used
+//
+//query: Which media to return
+func (api *InstagramAPI) LikedQuery(query MediaQuery) ([]Media, Pagination, error) {
+	return api.LikedQueryContext(context.Background(), query)
+}
+
+//Same as LikedQuery, but the request is bound to ctx
+func (api *InstagramAPI) LikedQueryContext(ctx context.Context, query MediaQuery) ([]Media, Pagination, error) {
+	params := MediaQuery{Count: query.Count}.params(false)
+	if query.Before != "" {
+		params["max_like_id"] = query.Before
+	}
+	return api.mediaList(ctx, "users/self/media/liked", params)
 }